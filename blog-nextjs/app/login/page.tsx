@@ -6,7 +6,12 @@
 
 import Logo from "@/components/ui/Logo";
 import { getCaptcha, sendEmailVerificationCode } from "@/lib/api/base";
-import { login, register } from "@/lib/api/user";
+import {
+  isTwoFactorChallenge,
+  login,
+  register,
+  twoFactorLogin,
+} from "@/lib/api/user";
 import siteConfig from "@/lib/constants/siteConfig";
 import { useUserStore } from "@/lib/store/userStore";
 import { ArrowLeft, Eye, EyeOff, Lock, Mail, User } from "lucide-react";
@@ -30,6 +35,10 @@ export default function LoginPage() {
   const [loginPassword, setLoginPassword] = useState("");
   const [showLoginPassword, setShowLoginPassword] = useState(false);
 
+  // Two-factor step
+  const [twoFactorToken, setTwoFactorToken] = useState("");
+  const [twoFactorCode, setTwoFactorCode] = useState("");
+
   // Register form
   const [registerUsername, setRegisterUsername] = useState("");
   const [registerEmail, setRegisterEmail] = useState("");
@@ -116,6 +125,11 @@ export default function LoginPage() {
         captcha_id: captchaId,
       });
 
+      if (isTwoFactorChallenge(userInfo)) {
+        setTwoFactorToken(userInfo.two_factor_token);
+        return;
+      }
+
       setUserLogin(userInfo);
       router.push("/dashboard");
     } catch (error: any) {
@@ -129,6 +143,40 @@ export default function LoginPage() {
     }
   };
 
+  const handleTwoFactorLogin = async (e: React.FormEvent) => {
+    e.preventDefault();
+    setError("");
+
+    if (!twoFactorCode.trim()) {
+      setError("Please enter the authentication code");
+      return;
+    }
+
+    try {
+      setLoading(true);
+      const userInfo = await twoFactorLogin({
+        two_factor_token: twoFactorToken,
+        code: twoFactorCode.trim(),
+      });
+
+      setUserLogin(userInfo);
+      router.push("/dashboard");
+    } catch (error: any) {
+      setError(
+        error.response?.data?.msg || "Verification failed. Please try again.",
+      );
+    } finally {
+      setLoading(false);
+    }
+  };
+
+  const cancelTwoFactor = () => {
+    setTwoFactorToken("");
+    setTwoFactorCode("");
+    setError("");
+    loadCaptcha();
+  };
+
   const handleRegister = async (e: React.FormEvent) => {
     e.preventDefault();
     setError("");
@@ -207,8 +255,52 @@ export default function LoginPage() {
             </div>
           )}
 
+          {/* Two-Factor Form */}
+          {mode === "login" && twoFactorToken && (
+            <form onSubmit={handleTwoFactorLogin} className="space-y-5">
+              <div>
+                <label className="block text-sm font-medium text-foreground mb-2">
+                  Authentication Code
+                </label>
+                <div className="relative">
+                  <Lock className="absolute left-3 top-1/2 -translate-y-1/2 w-5 h-5 text-muted-foreground" />
+                  <input
+                    type="text"
+                    inputMode="numeric"
+                    autoComplete="one-time-code"
+                    value={twoFactorCode}
+                    onChange={(e) => setTwoFactorCode(e.target.value)}
+                    placeholder="6-digit code or a recovery code"
+                    className="w-full h-12 pl-11 pr-4 border-2 border-border rounded-lg focus:outline-none focus:ring-2 focus:ring-google-blue/50 focus:border-google-blue transition-colors"
+                    required
+                    autoFocus
+                  />
+                </div>
+                <p className="mt-2 text-xs text-muted-foreground">
+                  Enter the code from your authenticator app, or one of your
+                  recovery codes.
+                </p>
+              </div>
+
+              <button
+                type="submit"
+                disabled={loading}
+                className="w-full h-12 bg-google-blue text-white rounded-lg hover:bg-[hsl(214,90%,48%)] transition-colors font-medium disabled:opacity-50 disabled:cursor-not-allowed"
+              >
+                {loading ? "Verifying..." : "Verify"}
+              </button>
+              <button
+                type="button"
+                onClick={cancelTwoFactor}
+                className="w-full text-sm text-muted-foreground hover:text-foreground"
+              >
+                Back to sign in
+              </button>
+            </form>
+          )}
+
           {/* Login Form */}
-          {mode === "login" && (
+          {mode === "login" && !twoFactorToken && (
             <form onSubmit={handleLogin} className="space-y-5">
               {/* Email */}
               <div>
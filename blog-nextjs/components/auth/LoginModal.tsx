@@ -8,7 +8,9 @@ import { getCaptcha } from "@/lib/api/base";
 import {
   login as apiLogin,
   register as apiRegister,
+  isTwoFactorChallenge,
   sendEmailVerificationCode,
+  twoFactorLogin,
 } from "@/lib/api/user";
 import { useUIStore } from "@/lib/store/uiStore";
 import { useUserStore } from "@/lib/store/userStore";
@@ -39,6 +41,8 @@ export default function LoginModal() {
   const [loginEmail, setLoginEmail] = useState("");
   const [loginPassword, setLoginPassword] = useState("");
   const [showLoginPassword, setShowLoginPassword] = useState(false);
+  const [twoFactorToken, setTwoFactorToken] = useState("");
+  const [twoFactorCode, setTwoFactorCode] = useState("");
 
   // Register form state
   const [registerEmail, setRegisterEmail] = useState("");
@@ -81,6 +85,29 @@ export default function LoginModal() {
     setLoading(true);
     setError("");
 
+    // Second step for accounts with two-factor authentication
+    if (twoFactorToken) {
+      try {
+        const userInfo = await twoFactorLogin({
+          two_factor_token: twoFactorToken,
+          code: twoFactorCode.trim(),
+        });
+        storeLogin(userInfo);
+        setSuccess("Login successful!");
+        setTimeout(() => {
+          closeLoginModal();
+          router.push("/");
+        }, 300);
+      } catch (error: any) {
+        setError(
+          error.response?.data?.msg || "Verification failed. Please try again.",
+        );
+      } finally {
+        setLoading(false);
+      }
+      return;
+    }
+
     // Validate captcha
     if (!captchaInput.trim()) {
       setError("Please enter the captcha");
@@ -96,6 +123,11 @@ export default function LoginModal() {
         captcha_id: captchaId,
       });
 
+      if (isTwoFactorChallenge(userInfo)) {
+        setTwoFactorToken(userInfo.two_factor_token);
+        return;
+      }
+
       // Store user data using the store's login method
       storeLogin(userInfo);
 
@@ -181,6 +213,8 @@ export default function LoginModal() {
     setSuccess("");
     setShowLoginPassword(false);
     setShowRegisterPassword(false);
+    setTwoFactorToken("");
+    setTwoFactorCode("");
     setCountdown(0);
   };
 
@@ -251,7 +285,39 @@ export default function LoginModal() {
             </div>
           )}
 
-          {mode === "login" ? (
+          {mode === "login" && twoFactorToken ? (
+            // Two-Factor Form
+            <div className="space-y-4">
+              <div>
+                <label className="block text-sm font-medium text-gray-700 mb-2">
+                  Authentication Code
+                </label>
+                <div className="relative">
+                  <Lock className="absolute left-3 top-1/2 -translate-y-1/2 w-4 h-4 text-gray-400" />
+                  <input
+                    type="text"
+                    inputMode="numeric"
+                    autoComplete="one-time-code"
+                    value={twoFactorCode}
+                    onChange={(e) => setTwoFactorCode(e.target.value)}
+                    className="w-full pl-10 pr-4 py-2 border border-gray-300 rounded-lg focus:outline-none focus:ring-2 focus:ring-google-blue/50 focus:border-google-blue"
+                    placeholder="6-digit code or a recovery code"
+                    required
+                    autoFocus
+                  />
+                </div>
+              </div>
+
+              {/* Submit Button */}
+              <button
+                type="submit"
+                disabled={loading}
+                className="w-full py-3 bg-google-blue text-white rounded-lg hover:bg-[hsl(214,90%,48%)] transition-colors font-medium disabled:opacity-50 disabled:cursor-not-allowed"
+              >
+                {loading ? "Verifying..." : "Verify"}
+              </button>
+            </div>
+          ) : mode === "login" ? (
             // Login Form
             <div className="space-y-4">
               {/* Email */}
@@ -153,10 +153,11 @@ export async function getCaptcha(): Promise<CaptchaResponse> {
 }
 
 /**
- * Send email verification code
+ * Send email verification code, purpose defaults to "register"
  */
 export async function sendEmailVerificationCode(data: {
   email: string;
+  purpose?: "register" | "forgot_password" | "change_email";
   captcha: string;
   captcha_id: string;
 }): Promise<void> {
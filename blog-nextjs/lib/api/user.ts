@@ -6,12 +6,16 @@ import type {
     LoginRequest,
     PaginatedResponse,
     RegisterRequest,
+    TwoFactorChallenge,
+    TwoFactorEnroll,
+    TwoFactorStatus,
     UpdateUserRequest,
+    UserSession,
     User,
     UserChartData,
     UserInfo,
 } from "@/types";
-import { get, post, put } from "./client";
+import { del, get, post, put } from "./client";
 
 // Re-export sendEmailVerificationCode from base API
 export { sendEmailVerificationCode } from "./base";
@@ -21,10 +25,31 @@ export { sendEmailVerificationCode } from "./base";
 // ----------------------------------------------------------------------------
 
 /**
- * User login
+ * User login, accounts with two-factor authentication get a challenge instead of tokens
  */
-export async function login(data: LoginRequest): Promise<UserInfo> {
-  return post<UserInfo>("/user/login", data);
+export async function login(
+  data: LoginRequest,
+): Promise<UserInfo | TwoFactorChallenge> {
+  return post<UserInfo | TwoFactorChallenge>("/user/login", data);
+}
+
+/**
+ * Second login step with an authenticator code or a recovery code
+ */
+export async function twoFactorLogin(data: {
+  two_factor_token: string;
+  code: string;
+}): Promise<UserInfo> {
+  return post<UserInfo>("/user/twoFactorLogin", data);
+}
+
+/**
+ * Check whether a login response requires the two-factor step
+ */
+export function isTwoFactorChallenge(
+  res: UserInfo | TwoFactorChallenge,
+): res is TwoFactorChallenge {
+  return "two_factor_required" in res && res.two_factor_required;
 }
 
 /**
@@ -62,6 +87,74 @@ export async function resetPassword(data: {
   return put<void>("/user/resetPassword", data);
 }
 
+// ----------------------------------------------------------------------------
+// Sessions
+// ----------------------------------------------------------------------------
+
+/**
+ * Get active login sessions of the current user
+ */
+export async function getSessions(): Promise<PaginatedResponse<UserSession>> {
+  return get<PaginatedResponse<UserSession>>("/user/sessions");
+}
+
+/**
+ * Revoke one login session of the current user
+ */
+export async function revokeSession(sessionId: string): Promise<void> {
+  return del<void>("/user/session", { data: { session_id: sessionId } });
+}
+
+/**
+ * Revoke all login sessions except the current one
+ */
+export async function revokeOtherSessions(): Promise<void> {
+  return del<void>("/user/sessions/others");
+}
+
+// ----------------------------------------------------------------------------
+// Two-Factor Authentication
+// ----------------------------------------------------------------------------
+
+/**
+ * Get two-factor authentication status
+ */
+export async function getTwoFactorStatus(): Promise<TwoFactorStatus> {
+  return get<TwoFactorStatus>("/twoFactor/status");
+}
+
+/**
+ * Start enrollment, returns the secret and the otpauth URL
+ */
+export async function enrollTwoFactor(): Promise<TwoFactorEnroll> {
+  return post<TwoFactorEnroll>("/twoFactor/enroll");
+}
+
+/**
+ * Confirm enrollment, returns the recovery codes once
+ */
+export async function confirmTwoFactor(data: {
+  code: string;
+}): Promise<{ recovery_codes: string[] }> {
+  return post<{ recovery_codes: string[] }>("/twoFactor/confirm", data);
+}
+
+/**
+ * Regenerate recovery codes
+ */
+export async function regenerateRecoveryCodes(data: {
+  code: string;
+}): Promise<{ recovery_codes: string[] }> {
+  return post<{ recovery_codes: string[] }>("/twoFactor/recoveryCodes", data);
+}
+
+/**
+ * Disable two-factor authentication
+ */
+export async function disableTwoFactor(data: { code: string }): Promise<void> {
+  return post<void>("/twoFactor/disable", data);
+}
+
 // ----------------------------------------------------------------------------
 // User Information
 // ----------------------------------------------------------------------------
@@ -80,6 +173,27 @@ export async function updateUserInfo(data: UpdateUserRequest): Promise<User> {
   return put<User>("/user/changeInfo", data);
 }
 
+/**
+ * Change email, a "change_email" verification code must be sent to the new address first
+ */
+export async function changeEmail(data: {
+  email: string;
+  verification_code: string;
+}): Promise<void> {
+  return put<void>("/user/changeEmail", data);
+}
+
+/**
+ * Delete the current account, accounts with a password need the password, QQ accounts with an email need a
+ * delete_account email code, other QQ accounts need to have logged in within the last 10 minutes
+ */
+export async function deleteAccount(data: {
+  password?: string;
+  verification_code?: string;
+}): Promise<void> {
+  return del<void>("/user/deleteAccount", { data });
+}
+
 /**
  * Get user card info by UUID
  */
@@ -137,6 +251,13 @@ export async function unfreezeUser(id: number): Promise<void> {
   return put<void>("/user/unfreeze", { id });
 }
 
+/**
+ * Revoke all login sessions of a user (Admin)
+ */
+export async function revokeUserSessions(id: number): Promise<void> {
+  return put<void>("/user/revokeSessions", { id });
+}
+
 /**
  * Get login logs (Admin)
  */
@@ -88,6 +88,37 @@ export interface UserInfo {
   access_token_expires_at: string;
 }
 
+export interface UserSession {
+  session_id: string;
+  user_id: number;
+  ip: string;
+  address: string;
+  os: string;
+  device_info: string;
+  browser_info: string;
+  last_seen_at: string;
+  expires_at: string;
+  created_at: string;
+  current: boolean;
+}
+
+export interface TwoFactorChallenge {
+  two_factor_required: true;
+  two_factor_token: string;
+  expires_at: number;
+}
+
+export interface TwoFactorStatus {
+  enabled: boolean;
+  required: boolean;
+  recovery_codes: number;
+}
+
+export interface TwoFactorEnroll {
+  secret: string;
+  url: string;
+}
+
 export interface LoginRequest {
   email: string;
   password: string;
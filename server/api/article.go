@@ -1,12 +1,19 @@
 package api
 
 import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
 	"server/global"
 	"server/model/request"
 	"server/model/response"
 	"server/utils"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid"
 	"go.uber.org/zap"
 )
 
@@ -28,6 +35,63 @@ func (articleApi *ArticleApi) ArticleInfoByID(c *gin.Context) {
 		response.FailWithMessage("Failed to get article information", c)
 		return
 	}
+	recordArticleView(c, req.ID)
+	response.OkWithData(article, c)
+}
+
+// recordArticleView 异步更新浏览量，登录用户按用户去重，游客按 IP 和 User-Agent 去重
+func recordArticleView(c *gin.Context, id string) {
+	viewer := "guest:" + utils.MD5V([]byte(c.ClientIP()+"|"+c.Request.UserAgent()))
+	if utils.GetAccessToken(c) != "" {
+		if userUUID := utils.GetUUID(c); userUUID != uuid.Nil {
+			viewer = "user:" + userUUID.String()
+		}
+	}
+	go func() {
+		if err := articleService.ArticleViewed(id, viewer); err != nil {
+			global.Log.Error("Failed to record article view:", zap.Error(err))
+		}
+	}()
+}
+
+// ArticleRelated 获取相关文章推荐
+func (articleApi *ArticleApi) ArticleRelated(c *gin.Context) {
+	var req request.ArticleInfoByID
+	err := c.ShouldBindUri(&req)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+
+	list, err := articleService.ArticleRelated(req.ID)
+	if err != nil {
+		global.Log.Error("Failed to get related articles:", zap.Error(err))
+		response.FailWithMessage("Failed to get related articles", c)
+		return
+	}
+	response.OkWithData(list, c)
+}
+
+// ArticleInfoBySlug 根据文章 slug 获取文章内容，历史 slug 会被重定向到当前的 slug
+func (articleApi *ArticleApi) ArticleInfoBySlug(c *gin.Context) {
+	var req request.ArticleInfoBySlug
+	err := c.ShouldBindUri(&req)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+
+	article, redirectSlug, err := articleService.ArticleInfoBySlug(req.Slug)
+	if err != nil {
+		global.Log.Error("Failed to get article information:", zap.Error(err))
+		response.FailWithMessage("Failed to get article information", c)
+		return
+	}
+	if redirectSlug != "" {
+		c.Redirect(http.StatusMovedPermanently, path.Join("/", global.Config.System.RouterPrefix, "article/slug", url.PathEscape(redirectSlug)))
+		return
+	}
+	recordArticleView(c, article.Id_)
 	response.OkWithData(article, c)
 }
 
@@ -40,18 +104,37 @@ func (articleApi *ArticleApi) ArticleSearch(c *gin.Context) {
 		return
 	}
 
-	list, total, err := articleService.ArticleSearch(info)
+	list, total, facets, err := articleService.ArticleSearch(info)
 	if err != nil {
 		global.Log.Error("Failed to get article search results:", zap.Error(err))
 		response.FailWithMessage("Failed to get article search results", c)
 		return
 	}
-	response.OkWithData(response.PageResult{
-		List:  list,
-		Total: total,
+	response.OkWithData(response.ArticleSearch{
+		List:   list,
+		Total:  total,
+		Facets: facets,
 	}, c)
 }
 
+// ArticleSuggest 搜索建议
+func (articleApi *ArticleApi) ArticleSuggest(c *gin.Context) {
+	var req request.ArticleSuggest
+	err := c.ShouldBindQuery(&req)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+
+	list, err := articleService.ArticleSuggest(req)
+	if err != nil {
+		global.Log.Error("Failed to get search suggestions:", zap.Error(err))
+		response.FailWithMessage("Failed to get search suggestions", c)
+		return
+	}
+	response.OkWithData(list, c)
+}
+
 // ArticleCategory 获取所有文章类别及数量
 func (articleApi *ArticleApi) ArticleCategory(c *gin.Context) {
 	category, err := articleService.ArticleCategory()
@@ -179,6 +262,7 @@ func (articleApi *ArticleApi) ArticleUpdate(c *gin.Context) {
 		return
 	}
 
+	req.EditorID = utils.GetUserID(c)
 	err = articleService.ArticleUpdate(req)
 	if err != nil {
 		global.Log.Error("Failed to update article:", zap.Error(err))
@@ -188,6 +272,42 @@ func (articleApi *ArticleApi) ArticleUpdate(c *gin.Context) {
 	response.OkWithMessage("Successfully updated article", c)
 }
 
+// ArticleStatusUpdate 修改文章状态，草稿、定时发布、已发布或已归档
+func (articleApi *ArticleApi) ArticleStatusUpdate(c *gin.Context) {
+	var req request.ArticleStatusUpdate
+	err := c.ShouldBindJSON(&req)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+
+	err = articleService.ArticleStatusUpdate(req)
+	if err != nil {
+		global.Log.Error("Failed to update article status:", zap.Error(err))
+		response.FailWithMessage("Failed to update article status", c)
+		return
+	}
+	response.OkWithMessage("Successfully updated article status", c)
+}
+
+// ArticleDetail 获取文章内容，包括未发布的文章
+func (articleApi *ArticleApi) ArticleDetail(c *gin.Context) {
+	var req request.ArticleInfoByID
+	err := c.ShouldBindUri(&req)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+
+	article, err := articleService.ArticleDetail(req.ID)
+	if err != nil {
+		global.Log.Error("Failed to get article information:", zap.Error(err))
+		response.FailWithMessage("Failed to get article information", c)
+		return
+	}
+	response.OkWithData(article, c)
+}
+
 // ArticleList 获取文章列表
 func (articleApi *ArticleApi) ArticleList(c *gin.Context) {
 	var pageInfo request.ArticleList
@@ -208,3 +328,150 @@ func (articleApi *ArticleApi) ArticleList(c *gin.Context) {
 		Total: total,
 	}, c)
 }
+
+// ArticleRevisionList 获取文章历史版本列表
+func (articleApi *ArticleApi) ArticleRevisionList(c *gin.Context) {
+	var pageInfo request.ArticleRevisionList
+	err := c.ShouldBindQuery(&pageInfo)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+
+	list, total, err := articleService.ArticleRevisionList(pageInfo)
+	if err != nil {
+		global.Log.Error("Failed to get article revision list:", zap.Error(err))
+		response.FailWithMessage("Failed to get article revision list", c)
+		return
+	}
+	response.OkWithData(response.PageResult{
+		List:  list,
+		Total: total,
+	}, c)
+}
+
+// ArticleRevisionDiff 比较文章的两个历史版本
+func (articleApi *ArticleApi) ArticleRevisionDiff(c *gin.Context) {
+	var req request.ArticleRevisionDiff
+	err := c.ShouldBindQuery(&req)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+
+	diff, err := articleService.ArticleRevisionDiff(req)
+	if err != nil {
+		global.Log.Error("Failed to get article revision diff:", zap.Error(err))
+		response.FailWithMessage("Failed to get article revision diff", c)
+		return
+	}
+	response.OkWithData(diff, c)
+}
+
+// ArticleRevisionRestore 将文章回滚到指定的历史版本
+func (articleApi *ArticleApi) ArticleRevisionRestore(c *gin.Context) {
+	var req request.ArticleRevisionRestore
+	err := c.ShouldBindJSON(&req)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+
+	req.EditorID = utils.GetUserID(c)
+	err = articleService.ArticleRevisionRestore(req)
+	if err != nil {
+		global.Log.Error("Failed to restore article revision:", zap.Error(err))
+		response.FailWithMessage("Failed to restore article revision", c)
+		return
+	}
+	response.OkWithMessage("Successfully restored article revision", c)
+}
+
+// ArticleImport 导入 Markdown 文章，支持多个 .md 文件或 zip 压缩包
+// front matter 中未设置 status 的文章按已发布导入，publish_at 作为文章的发布时间
+func (articleApi *ArticleApi) ArticleImport(c *gin.Context) {
+	form, err := c.MultipartForm()
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+
+	total, errs := articleService.ArticleImportFiles(form.File["files"])
+	result := response.ArticleImport{Total: total, Errors: []string{}}
+	for _, err := range errs {
+		global.Log.Error("Failed to import article:", zap.Error(err))
+		result.Errors = append(result.Errors, err.Error())
+	}
+	if len(errs) > 0 && total == 0 {
+		response.FailWithDetailed(result, "Failed to import articles", c)
+		return
+	}
+	response.OkWithDetailed(result, fmt.Sprintf("Successfully imported %d articles", total), c)
+}
+
+// ArticleExport 将所有文章导出为 Markdown 文件的 zip 压缩包
+func (articleApi *ArticleApi) ArticleExport(c *gin.Context) {
+	var buf bytes.Buffer
+	if err := articleService.ArticleExport(&buf); err != nil {
+		global.Log.Error("Failed to export articles:", zap.Error(err))
+		response.FailWithMessage("Failed to export articles", c)
+		return
+	}
+	fileName := fmt.Sprintf("articles_%s.zip", time.Now().Format("20060102"))
+	c.Header("Content-Disposition", `attachment; filename="`+fileName+`"`)
+	c.Data(http.StatusOK, "application/zip", buf.Bytes())
+}
+
+// ArticleFeatured 获取精选文章
+func (articleApi *ArticleApi) ArticleFeatured(c *gin.Context) {
+	var req request.ArticleFeatured
+	err := c.ShouldBindQuery(&req)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+
+	list, err := articleService.ArticleFeatured(req)
+	if err != nil {
+		global.Log.Error("Failed to get featured articles:", zap.Error(err))
+		response.FailWithMessage("Failed to get featured articles", c)
+		return
+	}
+	response.OkWithData(list, c)
+}
+
+// ArticlePin 置顶或取消置顶文章
+func (articleApi *ArticleApi) ArticlePin(c *gin.Context) {
+	var req request.ArticlePin
+	err := c.ShouldBindJSON(&req)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+
+	err = articleService.ArticlePin(req)
+	if err != nil {
+		global.Log.Error("Failed to update article pin:", zap.Error(err))
+		response.FailWithMessage("Failed to update article pin", c)
+		return
+	}
+	response.OkWithMessage("Successfully updated article pin", c)
+}
+
+// ArticleFeature 设置或取消精选文章
+func (articleApi *ArticleApi) ArticleFeature(c *gin.Context) {
+	var req request.ArticleFeature
+	err := c.ShouldBindJSON(&req)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+
+	err = articleService.ArticleFeature(req)
+	if err != nil {
+		global.Log.Error("Failed to update article feature:", zap.Error(err))
+		response.FailWithMessage("Failed to update article feature", c)
+		return
+	}
+	response.OkWithMessage("Successfully updated article feature", c)
+}
@@ -0,0 +1,26 @@
+package api
+
+import (
+	"server/global"
+	"server/model/response"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type BackupApi struct {
+}
+
+// BackupList 获取自动备份列表
+func (backupApi *BackupApi) BackupList(c *gin.Context) {
+	list, err := backupService.BackupList()
+	if err != nil {
+		global.Log.Error("Failed to get backup list:", zap.Error(err))
+		response.FailWithMessage("Failed to get backup list", c)
+		return
+	}
+	response.OkWithData(response.PageResult{
+		List:  list,
+		Total: int64(len(list)),
+	}, c)
+}
@@ -1,9 +1,12 @@
 package api
 
 import (
+	"errors"
 	"server/global"
+	"server/model/appTypes"
 	"server/model/request"
 	"server/model/response"
+	"server/service"
 
 	"github.com/gin-gonic/gin"
 	"github.com/mojocn/base64Captcha"
@@ -51,8 +54,15 @@ func (baseApi *BaseApi) SendEmailVerificationCode(c *gin.Context) {
 		response.FailWithMessage(err.Error(), c)
 		return
 	}
+	if req.Purpose == "" {
+		req.Purpose = appTypes.EmailRegister
+	}
 	if store.Verify(req.CaptchaID, req.Captcha, true) {
-		err = baseService.SendEmailVerificationCode(c, req.Email)
+		err = baseService.SendEmailVerificationCode(req.Email, req.Purpose, c.ClientIP())
+		if errors.Is(err, service.ErrEmailCodeTooFrequent) {
+			response.FailWithMessage(err.Error(), c)
+			return
+		}
 		if err != nil {
 			global.Log.Error("Failed to send email:", zap.Error(err))
 			response.FailWithMessage("Failed to send email", c)
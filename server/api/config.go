@@ -4,6 +4,7 @@ import (
 	"server/config"
 	"server/global"
 	"server/model/response"
+	"server/utils"
 
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
@@ -172,3 +173,26 @@ func (configApi *ConfigApi) UpdateGaode(c *gin.Context) {
 	}
 	response.OkWithMessage("Successfully updated gaode", c)
 }
+
+// GetTwoFactor 获取两步验证配置
+func (configApi *ConfigApi) GetTwoFactor(c *gin.Context) {
+	response.OkWithData(global.Config.TwoFactor, c)
+}
+
+// UpdateTwoFactor 更新两步验证配置
+func (configApi *ConfigApi) UpdateTwoFactor(c *gin.Context) {
+	var req config.TwoFactor
+	err := c.ShouldBindJSON(&req)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+
+	err = configService.UpdateTwoFactor(req, utils.GetUserID(c))
+	if err != nil {
+		global.Log.Error("Failed to update two factor:", zap.Error(err))
+		response.FailWithMessage("Failed to update two factor, "+err.Error(), c)
+		return
+	}
+	response.OkWithMessage("Successfully updated two factor", c)
+}
@@ -14,6 +14,10 @@ type ApiGroup struct {
 	WebsiteApi
 	ConfigApi
 	AIApi
+	SitemapApi
+	SeriesApi
+	BackupApi
+	TwoFactorApi
 }
 
 var ApiGroupApp = new(ApiGroup)
@@ -21,7 +25,6 @@ var ApiGroupApp = new(ApiGroup)
 var baseService = service.ServiceGroupApp.BaseService
 var userService = service.ServiceGroupApp.UserService
 var qqService = service.ServiceGroupApp.QQService
-var jwtService = service.ServiceGroupApp.JwtService
 var imageService = service.ServiceGroupApp.ImageService
 var articleService = service.ServiceGroupApp.ArticleService
 var commentService = service.ServiceGroupApp.CommentService
@@ -30,3 +33,9 @@ var friendLinkService = service.ServiceGroupApp.FriendLinkService
 var feedbackService = service.ServiceGroupApp.FeedbackService
 var websiteService = service.ServiceGroupApp.WebsiteService
 var configService = service.ServiceGroupApp.ConfigService
+var feedService = service.ServiceGroupApp.FeedService
+var sitemapService = service.ServiceGroupApp.SitemapService
+var seriesService = service.ServiceGroupApp.SeriesService
+var backupService = service.ServiceGroupApp.BackupService
+var twoFactorService = service.ServiceGroupApp.TwoFactorService
+var sessionService = service.ServiceGroupApp.SessionService
@@ -0,0 +1,106 @@
+package api
+
+import (
+	"server/global"
+	"server/model/request"
+	"server/model/response"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type SeriesApi struct {
+}
+
+// SeriesInfo 获取系列信息，以及系列中按顺序排列的文章
+func (seriesApi *SeriesApi) SeriesInfo(c *gin.Context) {
+	var req request.SeriesInfo
+	err := c.ShouldBindUri(&req)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+
+	series, err := seriesService.SeriesInfo(req)
+	if err != nil {
+		global.Log.Error("Failed to get series information:", zap.Error(err))
+		response.FailWithMessage("Failed to get series information", c)
+		return
+	}
+	response.OkWithData(series, c)
+}
+
+// SeriesCreate 创建系列
+func (seriesApi *SeriesApi) SeriesCreate(c *gin.Context) {
+	var req request.SeriesCreate
+	err := c.ShouldBindJSON(&req)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+
+	err = seriesService.SeriesCreate(req)
+	if err != nil {
+		global.Log.Error("Failed to create series:", zap.Error(err))
+		response.FailWithMessage("Failed to create series", c)
+		return
+	}
+	response.OkWithMessage("Successfully created series", c)
+}
+
+// SeriesDelete 删除系列
+func (seriesApi *SeriesApi) SeriesDelete(c *gin.Context) {
+	var req request.SeriesDelete
+	err := c.ShouldBindJSON(&req)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+
+	err = seriesService.SeriesDelete(req)
+	if err != nil {
+		global.Log.Error("Failed to delete series:", zap.Error(err))
+		response.FailWithMessage("Failed to delete series", c)
+		return
+	}
+	response.OkWithMessage("Successfully deleted series", c)
+}
+
+// SeriesUpdate 更新系列
+func (seriesApi *SeriesApi) SeriesUpdate(c *gin.Context) {
+	var req request.SeriesUpdate
+	err := c.ShouldBindJSON(&req)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+
+	err = seriesService.SeriesUpdate(req)
+	if err != nil {
+		global.Log.Error("Failed to update series:", zap.Error(err))
+		response.FailWithMessage("Failed to update series", c)
+		return
+	}
+	response.OkWithMessage("Successfully updated series", c)
+}
+
+// SeriesList 获取系列列表
+func (seriesApi *SeriesApi) SeriesList(c *gin.Context) {
+	var pageInfo request.SeriesList
+	err := c.ShouldBindQuery(&pageInfo)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+
+	list, total, err := seriesService.SeriesList(pageInfo)
+	if err != nil {
+		global.Log.Error("Failed to get series list:", zap.Error(err))
+		response.FailWithMessage("Failed to get series list", c)
+		return
+	}
+	response.OkWithData(response.PageResult{
+		List:  list,
+		Total: total,
+	}, c)
+}
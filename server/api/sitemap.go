@@ -0,0 +1,47 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"server/global"
+	"server/model/response"
+	"server/service"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type SitemapApi struct {
+}
+
+// SitemapIndex 站点地图索引
+func (sitemapApi *SitemapApi) SitemapIndex(c *gin.Context) {
+	data, err := sitemapService.SitemapIndex()
+	if err != nil {
+		global.Log.Error("Failed to get sitemap index:", zap.Error(err))
+		response.FailWithMessage("Failed to get sitemap index", c)
+		return
+	}
+	serveCacheable(c, "application/xml; charset=utf-8", data, time.Time{})
+}
+
+// Sitemap 分页的站点地图，包括文章、类别和标签
+func (sitemapApi *SitemapApi) Sitemap(c *gin.Context) {
+	data, err := sitemapService.Sitemap(c.Param("name"))
+	if errors.Is(err, service.ErrSitemapNotFound) {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	if err != nil {
+		global.Log.Error("Failed to get sitemap:", zap.Error(err))
+		response.FailWithMessage("Failed to get sitemap", c)
+		return
+	}
+	serveCacheable(c, "application/xml; charset=utf-8", data, time.Time{})
+}
+
+// Robots robots.txt
+func (sitemapApi *SitemapApi) Robots(c *gin.Context) {
+	c.String(http.StatusOK, sitemapService.Robots())
+}
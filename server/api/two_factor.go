@@ -0,0 +1,104 @@
+package api
+
+import (
+	"errors"
+	"server/global"
+	"server/model/request"
+	"server/model/response"
+	"server/service"
+	"server/utils"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type TwoFactorApi struct {
+}
+
+// TwoFactorStatus 获取两步验证状态
+func (twoFactorApi *TwoFactorApi) TwoFactorStatus(c *gin.Context) {
+	status, err := twoFactorService.TwoFactorStatus(utils.GetUserID(c))
+	if err != nil {
+		global.Log.Error("Failed to get two-factor status:", zap.Error(err))
+		response.FailWithMessage("Failed to get two-factor status", c)
+		return
+	}
+	response.OkWithData(status, c)
+}
+
+// TwoFactorEnroll 生成两步验证密钥，返回供身份验证器使用的 otpauth 链接
+func (twoFactorApi *TwoFactorApi) TwoFactorEnroll(c *gin.Context) {
+	enroll, err := twoFactorService.TwoFactorEnroll(utils.GetUserID(c))
+	if err != nil {
+		global.Log.Error("Failed to enroll two-factor authentication:", zap.Error(err))
+		response.FailWithMessage("Failed to enroll two-factor authentication", c)
+		return
+	}
+	response.OkWithData(enroll, c)
+}
+
+// TwoFactorConfirm 确认并启用两步验证，返回恢复码
+func (twoFactorApi *TwoFactorApi) TwoFactorConfirm(c *gin.Context) {
+	var req request.TwoFactorCode
+	err := c.ShouldBindJSON(&req)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	req.UserID = utils.GetUserID(c)
+	codes, err := twoFactorService.TwoFactorConfirm(req)
+	if errors.Is(err, service.ErrTwoFactorInvalidCode) {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	if err != nil {
+		global.Log.Error("Failed to enable two-factor authentication:", zap.Error(err))
+		response.FailWithMessage("Failed to enable two-factor authentication", c)
+		return
+	}
+	response.OkWithDetailed(response.TwoFactorRecoveryCodes{RecoveryCodes: codes}, "Successfully enabled two-factor authentication", c)
+}
+
+// TwoFactorRecoveryCodes 重新生成恢复码
+func (twoFactorApi *TwoFactorApi) TwoFactorRecoveryCodes(c *gin.Context) {
+	var req request.TwoFactorCode
+	err := c.ShouldBindJSON(&req)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	req.UserID = utils.GetUserID(c)
+	codes, err := twoFactorService.TwoFactorRecoveryCodes(req)
+	if errors.Is(err, service.ErrTwoFactorInvalidCode) {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	if err != nil {
+		global.Log.Error("Failed to regenerate recovery codes:", zap.Error(err))
+		response.FailWithMessage("Failed to regenerate recovery codes", c)
+		return
+	}
+	response.OkWithData(response.TwoFactorRecoveryCodes{RecoveryCodes: codes}, c)
+}
+
+// TwoFactorDisable 关闭两步验证
+func (twoFactorApi *TwoFactorApi) TwoFactorDisable(c *gin.Context) {
+	var req request.TwoFactorCode
+	err := c.ShouldBindJSON(&req)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	req.UserID = utils.GetUserID(c)
+	err = twoFactorService.TwoFactorDisable(req)
+	if errors.Is(err, service.ErrTwoFactorInvalidCode) {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	if err != nil {
+		global.Log.Error("Failed to disable two-factor authentication:", zap.Error(err))
+		response.FailWithMessage("Failed to disable two-factor authentication", c)
+		return
+	}
+	response.OkWithMessage("Successfully disabled two-factor authentication", c)
+}
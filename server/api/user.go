@@ -3,15 +3,16 @@ package api
 import (
 	"errors"
 	"server/global"
+	"server/model/appTypes"
 	"server/model/database"
 	"server/model/request"
 	"server/model/response"
+	"server/service"
 	"server/utils"
 	"time"
 
-	"github.com/gin-contrib/sessions"
 	"github.com/gin-gonic/gin"
-	"github.com/go-redis/redis"
+	"github.com/gofrs/uuid"
 	"go.uber.org/zap"
 )
 
@@ -27,25 +28,7 @@ func (userApi *UserApi) Register(c *gin.Context) {
 		return
 	}
 
-	session := sessions.Default(c)
-	// 两次邮箱一致性判断
-	savedEmail := session.Get("email")
-	if savedEmail == nil || savedEmail.(string) != req.Email {
-		response.FailWithMessage("This email doesn't match the email to be verified", c)
-		return
-	}
-
-	// 获取会话中存储的邮箱验证码
-	savedCode := session.Get("verification_code")
-	if savedCode == nil || savedCode.(string) != req.VerificationCode {
-		response.FailWithMessage("Invalid verification code", c)
-		return
-	}
-
-	// 判断邮箱验证码是否过期
-	savedTime := session.Get("expire_time")
-	if savedTime.(int64) < time.Now().Unix() {
-		response.FailWithMessage("The verification code has expired, please resend it", c)
+	if !userApi.verifyEmailCode(c, req.Email, appTypes.EmailRegister, req.VerificationCode) {
 		return
 	}
 
@@ -62,6 +45,21 @@ func (userApi *UserApi) Register(c *gin.Context) {
 	userApi.TokenNext(c, user)
 }
 
+// verifyEmailCode 校验邮箱验证码，校验失败时写入错误响应并返回 false
+func (userApi *UserApi) verifyEmailCode(c *gin.Context, email string, purpose appTypes.EmailPurpose, code string) bool {
+	err := baseService.VerifyEmailCode(email, purpose, code)
+	if errors.Is(err, service.ErrEmailCodeInvalid) || errors.Is(err, service.ErrEmailCodeExpired) {
+		response.FailWithMessage(err.Error(), c)
+		return false
+	}
+	if err != nil {
+		global.Log.Error("Failed to verify email verification code:", zap.Error(err))
+		response.FailWithMessage("Failed to verify email verification code", c)
+		return false
+	}
+	return true
+}
+
 // Login 登录接口，根据不同的登录方式调用不同的登录方法
 func (userApi *UserApi) Login(c *gin.Context) {
 	switch c.Query("flag") {
@@ -128,6 +126,30 @@ func (userApi *UserApi) QQLogin(c *gin.Context) {
 	userApi.TokenNext(c, user)
 }
 
+// TwoFactorLogin 两步验证登录的第二步，提交验证码或恢复码后签发令牌
+func (userApi *UserApi) TwoFactorLogin(c *gin.Context) {
+	var req request.TwoFactorLogin
+	err := c.ShouldBindJSON(&req)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	req.IP = c.ClientIP()
+	user, err := twoFactorService.TwoFactorLogin(req)
+	if errors.Is(err, service.ErrTwoFactorInvalidCode) || errors.Is(err, service.ErrTwoFactorLoginExpired) ||
+		errors.Is(err, service.ErrTwoFactorLocked) || errors.Is(err, service.ErrTwoFactorThrottled) {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	if err != nil {
+		global.Log.Error("Failed to login:", zap.Error(err))
+		response.FailWithMessage("Failed to login", c)
+		return
+	}
+	userApi.issueTokens(c, user)
+}
+
+// TokenNext 登录成功后签发令牌，启用了两步验证的用户需要先完成第二步登录
 func (userApi *UserApi) TokenNext(c *gin.Context, user database.User) {
 	// 检查用户是否被冻结
 	if user.Freeze {
@@ -135,10 +157,39 @@ func (userApi *UserApi) TokenNext(c *gin.Context, user database.User) {
 		return
 	}
 
+	enabled, err := twoFactorService.TwoFactorEnabled(user.ID)
+	if err != nil {
+		global.Log.Error("Failed to get two-factor status:", zap.Error(err))
+		response.FailWithMessage("Failed to login", c)
+		return
+	}
+	if enabled {
+		challenge, err := twoFactorService.TwoFactorChallenge(user.ID)
+		if err != nil {
+			global.Log.Error("Failed to create two-factor login:", zap.Error(err))
+			response.FailWithMessage("Failed to login", c)
+			return
+		}
+		response.OkWithDetailed(challenge, "Two-factor authentication required", c)
+		return
+	}
+
+	userApi.issueTokens(c, user)
+}
+
+// issueTokens 为本次登录创建会话，并签发访问令牌和刷新令牌
+func (userApi *UserApi) issueTokens(c *gin.Context, user database.User) {
+	// 检查用户是否被冻结
+	if user.Freeze {
+		response.FailWithMessage("The user is frozen, contact the administrator", c)
+		return
+	}
+
 	baseClaims := request.BaseClaims{
-		UserID: user.ID,
-		UUID:   user.UUID,
-		RoleID: user.RoleID,
+		UserID:    user.ID,
+		UUID:      user.UUID,
+		RoleID:    user.RoleID,
+		SessionID: uuid.Must(uuid.NewV4()).String(),
 	}
 
 	j := utils.NewJWT()
@@ -161,66 +212,28 @@ func (userApi *UserApi) TokenNext(c *gin.Context, user database.User) {
 		return
 	}
 
-	// 是否开启了多地点登录拦截
-	if !global.Config.System.UseMultipoint {
-		// 设置刷新令牌并返回
-		utils.SetRefreshToken(c, refreshToken, int(refreshClaims.ExpiresAt.Unix()-time.Now().Unix()))
-		c.Set("user_id", user.ID)
-		response.OkWithDetailed(response.Login{
-			User:                 user,
-			AccessToken:          accessToken,
-			AccessTokenExpiresAt: accessClaims.ExpiresAt.Unix() * 1000,
-		}, "Successful login", c)
-		return
-	}
-
-	// 检查 Redis 中是否已存在该用户的 JWT
-	if jwtStr, err := jwtService.GetRedisJWT(user.UUID); errors.Is(err, redis.Nil) {
-		// 不存在就设置新的
-		if err := jwtService.SetRedisJWT(refreshToken, user.UUID); err != nil {
-			global.Log.Error("Failed to set login status:", zap.Error(err))
-			response.FailWithMessage("Failed to set login status", c)
-			return
-		}
-
-		// 设置刷新令牌并返回
-		utils.SetRefreshToken(c, refreshToken, int(refreshClaims.ExpiresAt.Unix()-time.Now().Unix()))
-		c.Set("user_id", user.ID)
-		response.OkWithDetailed(response.Login{
-			User:                 user,
-			AccessToken:          accessToken,
-			AccessTokenExpiresAt: accessClaims.ExpiresAt.Unix() * 1000,
-		}, "Successful login", c)
-	} else if err != nil {
-		// 出现错误处理
+	// 创建会话，未开启多点登录时其他会话会被注销
+	err = sessionService.SessionCreate(database.UserSession{
+		SessionID: baseClaims.SessionID,
+		UserID:    user.ID,
+		IP:        c.ClientIP(),
+		ExpiresAt: refreshClaims.ExpiresAt.Time,
+	}, refreshToken)
+	if err != nil {
 		global.Log.Error("Failed to set login status:", zap.Error(err))
 		response.FailWithMessage("Failed to set login status", c)
-	} else {
-		// Redis 中已存在该用户的 JWT，将旧的 JWT 加入黑名单，并设置新的 token
-		var blacklist database.JwtBlacklist
-		blacklist.Jwt = jwtStr
-		if err := jwtService.JoinInBlacklist(blacklist); err != nil {
-			global.Log.Error("Failed to invalidate jwt:", zap.Error(err))
-			response.FailWithMessage("Failed to invalidate jwt", c)
-			return
-		}
-
-		// 设置新的 JWT 到 Redis
-		if err := jwtService.SetRedisJWT(refreshToken, user.UUID); err != nil {
-			global.Log.Error("Failed to set login status:", zap.Error(err))
-			response.FailWithMessage("Failed to set login status", c)
-			return
-		}
-
-		// 设置刷新令牌并返回
-		utils.SetRefreshToken(c, refreshToken, int(refreshClaims.ExpiresAt.Unix()-time.Now().Unix()))
-		c.Set("user_id", user.ID)
-		response.OkWithDetailed(response.Login{
-			User:                 user,
-			AccessToken:          accessToken,
-			AccessTokenExpiresAt: accessClaims.ExpiresAt.Unix() * 1000,
-		}, "Successful login", c)
+		return
 	}
+
+	// 设置刷新令牌并返回
+	utils.SetRefreshToken(c, refreshToken, int(refreshClaims.ExpiresAt.Unix()-time.Now().Unix()))
+	c.Set("user_id", user.ID)
+	c.Set("session_id", baseClaims.SessionID)
+	response.OkWithDetailed(response.Login{
+		User:                 user,
+		AccessToken:          accessToken,
+		AccessTokenExpiresAt: accessClaims.ExpiresAt.Unix() * 1000,
+	}, "Successful login", c)
 }
 
 // ForgotPassword 找回密码
@@ -232,26 +245,7 @@ func (userApi *UserApi) ForgotPassword(c *gin.Context) {
 		return
 	}
 
-	session := sessions.Default(c)
-
-	// 两次邮箱一致性判断
-	savedEmail := session.Get("email")
-	if savedEmail == nil || savedEmail.(string) != req.Email {
-		response.FailWithMessage("This email doesn't match the email to be verified", c)
-		return
-	}
-
-	// 获取会话中存储的邮箱验证码
-	savedCode := session.Get("verification_code")
-	if savedCode == nil || savedCode.(string) != req.VerificationCode {
-		response.FailWithMessage("Invalid verification code", c)
-		return
-	}
-
-	// 判断邮箱验证码是否过期
-	savedTime := session.Get("expire_time")
-	if savedTime.(int64) < time.Now().Unix() {
-		response.FailWithMessage("The verification code has expired, please resend it", c)
+	if !userApi.verifyEmailCode(c, req.Email, appTypes.EmailForgotPassword, req.VerificationCode) {
 		return
 	}
 
@@ -336,6 +330,96 @@ func (userApi *UserApi) UserChangeInfo(c *gin.Context) {
 	response.OkWithMessage("Successfully changed user information", c)
 }
 
+// UserChangeEmail 修改邮箱，需要先向新邮箱发送用途为 change_email 的验证码
+func (userApi *UserApi) UserChangeEmail(c *gin.Context) {
+	var req request.UserChangeEmail
+	err := c.ShouldBindJSON(&req)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	if !userApi.verifyEmailCode(c, req.Email, appTypes.EmailChangeEmail, req.VerificationCode) {
+		return
+	}
+	req.UserID = utils.GetUserID(c)
+	err = userService.UserChangeEmail(req)
+	if err != nil {
+		global.Log.Error("Failed to change email:", zap.Error(err))
+		response.FailWithMessage("Failed to change email", c)
+		return
+	}
+	response.OkWithMessage("Successfully changed email", c)
+}
+
+// UserDeleteAccount 注销账号
+func (userApi *UserApi) UserDeleteAccount(c *gin.Context) {
+	var req request.UserDeleteAccount
+	err := c.ShouldBindJSON(&req)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	req.UserID = utils.GetUserID(c)
+	req.SessionID = utils.GetSessionID(c)
+	err = userService.UserDeleteAccount(req)
+	if errors.Is(err, service.ErrEmailCodeInvalid) || errors.Is(err, service.ErrEmailCodeExpired) || errors.Is(err, service.ErrRecentLoginRequired) {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	if err != nil {
+		global.Log.Error("Failed to delete account:", zap.Error(err))
+		response.FailWithMessage("Failed to delete account", c)
+		return
+	}
+	response.OkWithMessage("Successfully deleted account", c)
+	userService.Logout(c)
+}
+
+// UserSessionList 获取当前用户的登录会话列表
+func (userApi *UserApi) UserSessionList(c *gin.Context) {
+	list, err := sessionService.SessionList(utils.GetUserID(c), utils.GetSessionID(c))
+	if err != nil {
+		global.Log.Error("Failed to get session list:", zap.Error(err))
+		response.FailWithMessage("Failed to get session list", c)
+		return
+	}
+	response.OkWithData(response.PageResult{
+		List:  list,
+		Total: int64(len(list)),
+	}, c)
+}
+
+// UserSessionRevoke 注销当前用户的一个登录会话
+func (userApi *UserApi) UserSessionRevoke(c *gin.Context) {
+	var req request.UserSessionRevoke
+	err := c.ShouldBindJSON(&req)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	err = sessionService.SessionRevoke(utils.GetUserID(c), req.SessionID)
+	if err != nil {
+		global.Log.Error("Failed to revoke session:", zap.Error(err))
+		response.FailWithMessage("Failed to revoke session", c)
+		return
+	}
+	if req.SessionID == utils.GetSessionID(c) {
+		utils.ClearRefreshToken(c)
+	}
+	response.OkWithMessage("Successfully revoked session", c)
+}
+
+// UserSessionRevokeOthers 注销当前用户除当前会话以外的所有登录会话
+func (userApi *UserApi) UserSessionRevokeOthers(c *gin.Context) {
+	err := sessionService.SessionRevokeAll(utils.GetUserID(c), utils.GetSessionID(c))
+	if err != nil {
+		global.Log.Error("Failed to revoke sessions:", zap.Error(err))
+		response.FailWithMessage("Failed to revoke sessions", c)
+		return
+	}
+	response.OkWithMessage("Successfully revoked other sessions", c)
+}
+
 // UserWeather 获取天气
 func (userApi *UserApi) UserWeather(c *gin.Context) {
 	ip := c.ClientIP()
@@ -441,3 +525,20 @@ func (userApi *UserApi) UserLoginList(c *gin.Context) {
 		Total: total,
 	}, c)
 }
+
+// UserRevokeSessions 注销指定用户的所有登录会话
+func (userApi *UserApi) UserRevokeSessions(c *gin.Context) {
+	var req request.UserOperation
+	err := c.ShouldBindJSON(&req)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	err = sessionService.SessionRevokeAll(req.ID, "")
+	if err != nil {
+		global.Log.Error("Failed to revoke sessions:", zap.Error(err))
+		response.FailWithMessage("Failed to revoke sessions", c)
+		return
+	}
+	response.OkWithMessage("Successfully revoked sessions", c)
+}
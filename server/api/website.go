@@ -6,6 +6,7 @@ import (
 	"server/model/database"
 	"server/model/request"
 	"server/model/response"
+	"server/utils"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -70,6 +71,60 @@ func (website *WebsiteApi) WebsiteFooterLink(c *gin.Context) {
 	response.OkWithData(footerLinks, c)
 }
 
+// WebsiteRSS 获取 RSS 2.0 订阅源
+func (website *WebsiteApi) WebsiteRSS(c *gin.Context) {
+	var req request.WebsiteFeed
+	err := c.ShouldBindQuery(&req)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+
+	data, lastModified, err := feedService.FeedRSS(req, requestURL(c))
+	if err != nil {
+		global.Log.Error("Failed to get rss feed:", zap.Error(err))
+		response.FailWithMessage("Failed to get rss feed", c)
+		return
+	}
+	serveCacheable(c, "application/rss+xml; charset=utf-8", data, lastModified)
+}
+
+// WebsiteAtom 获取 Atom 1.0 订阅源
+func (website *WebsiteApi) WebsiteAtom(c *gin.Context) {
+	var req request.WebsiteFeed
+	err := c.ShouldBindQuery(&req)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+
+	data, lastModified, err := feedService.FeedAtom(req, requestURL(c))
+	if err != nil {
+		global.Log.Error("Failed to get atom feed:", zap.Error(err))
+		response.FailWithMessage("Failed to get atom feed", c)
+		return
+	}
+	serveCacheable(c, "application/atom+xml; charset=utf-8", data, lastModified)
+}
+
+// WebsiteJSONFeed 获取 JSON Feed 1.1 订阅源
+func (website *WebsiteApi) WebsiteJSONFeed(c *gin.Context) {
+	var req request.WebsiteFeed
+	err := c.ShouldBindQuery(&req)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+
+	data, lastModified, err := feedService.FeedJSON(req, requestURL(c))
+	if err != nil {
+		global.Log.Error("Failed to get json feed:", zap.Error(err))
+		response.FailWithMessage("Failed to get json feed", c)
+		return
+	}
+	serveCacheable(c, "application/feed+json; charset=utf-8", data, lastModified)
+}
+
 // WebsiteAddCarousel 添加首页背景
 func (website *WebsiteApi) WebsiteAddCarousel(c *gin.Context) {
 	var req request.WebsiteCarouselOperation
@@ -145,3 +200,35 @@ func (website *WebsiteApi) WebsiteDeleteFooterLink(c *gin.Context) {
 	}
 	response.OkWithMessage("Successfully deleted footer link", c)
 }
+
+// serveCacheable 返回可被客户端缓存的内容，支持 ETag 和 If-Modified-Since 条件请求
+func serveCacheable(c *gin.Context, contentType string, data []byte, lastModified time.Time) {
+	etag := `"` + utils.MD5V(data) + `"`
+	c.Header("ETag", etag)
+	if !lastModified.IsZero() {
+		c.Header("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
+	}
+
+	// 优先使用 If-None-Match 判断，其次使用 If-Modified-Since 判断
+	if match := c.GetHeader("If-None-Match"); match != "" {
+		if match == etag || match == "W/"+etag {
+			c.Status(http.StatusNotModified)
+			return
+		}
+	} else if since := c.GetHeader("If-Modified-Since"); since != "" && !lastModified.IsZero() {
+		if t, err := http.ParseTime(since); err == nil && !lastModified.Truncate(time.Second).After(t) {
+			c.Status(http.StatusNotModified)
+			return
+		}
+	}
+	c.Data(http.StatusOK, contentType, data)
+}
+
+// requestURL 返回当前请求的完整地址
+func requestURL(c *gin.Context) string {
+	scheme := "http"
+	if c.Request.TLS != nil || c.GetHeader("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+	return scheme + "://" + c.Request.Host + c.Request.URL.RequestURI()
+}
@@ -0,0 +1,43 @@
+package config
+
+import (
+	"server/model/appTypes"
+	"strings"
+)
+
+// Backup 自动备份配置
+type Backup struct {
+	Enable  bool   `json:"enable" yaml:"enable"`     // 是否开启自动备份，true 表示开启，false 表示关闭
+	Spec    string `json:"spec" yaml:"spec"`         // 备份任务的 cron 表达式，例如 "0 3 * * *" 表示每天凌晨 3 点，默认为 "@daily"
+	Path    string `json:"path" yaml:"path"`         // 备份目录，使用七牛云存储时作为对象名称的前缀，默认为 backup
+	OssType string `json:"oss_type" yaml:"oss_type"` // 备份的存储类型，如 "local" 或 "qiniu"
+	Bucket  string `json:"bucket" yaml:"bucket"`     // 使用七牛云存储时的私有空间名称，不能与存放公开图片的空间相同，与图片空间使用相同的存储区域和秘钥
+	Daily   int    `json:"daily" yaml:"daily"`       // 保留最近多少天的每日备份
+	Weekly  int    `json:"weekly" yaml:"weekly"`     // 保留最近多少周的每周备份
+}
+
+// Schedule 返回备份任务的 cron 表达式
+func (b Backup) Schedule() string {
+	if b.Spec == "" {
+		return "@daily"
+	}
+	return b.Spec
+}
+
+// Dir 返回备份目录
+func (b Backup) Dir() string {
+	if b.Path == "" {
+		return "backup"
+	}
+	return strings.TrimSuffix(b.Path, "/")
+}
+
+// Storage 返回备份的存储类型
+func (b Backup) Storage() appTypes.Storage {
+	switch strings.ToLower(b.OssType) {
+	case "qiniu":
+		return appTypes.Qiniu
+	default:
+		return appTypes.Local
+	}
+}
@@ -6,4 +6,13 @@ type ES struct {
 	Username       string `json:"username" yaml:"username"`                 // 用于连接 Elasticsearch 的用户名
 	Password       string `json:"password" yaml:"password"`                 // 用于连接 Elasticsearch 的密码
 	IsConsolePrint bool   `json:"is_console_print" yaml:"is_console_print"` // 是否在控制台打印 Elasticsearch 语句，true 表示打印，false 表示不打印
+	BatchSize      int    `json:"batch_size" yaml:"batch_size"`             // 导入导出数据时每批处理的文档数量，默认为 1000
+}
+
+// Batch 返回导入导出数据时每批处理的文档数量
+func (es ES) Batch() int {
+	if es.BatchSize <= 0 {
+		return 1000
+	}
+	return es.BatchSize
 }
@@ -0,0 +1,15 @@
+package config
+
+// Sitemap 站点地图与 robots.txt 配置
+type Sitemap struct {
+	PageSize int      `json:"page_size" yaml:"page_size"` // 每个站点地图文件包含的最大链接数量，最多为 50000
+	Disallow []string `json:"disallow" yaml:"disallow"`   // robots.txt 中禁止搜索引擎抓取的路径，例如 /dashboard
+}
+
+// Limit 返回每个站点地图文件实际包含的最大链接数量
+func (s Sitemap) Limit() int {
+	if s.PageSize <= 0 || s.PageSize > 50000 {
+		return 50000
+	}
+	return s.PageSize
+}
@@ -15,12 +15,21 @@ type System struct {
 	UseMultipoint  bool   `json:"use_multipoint" yaml:"use_multipoint"`   // 是否启用多点登录拦截，防止同一账户在多个地方同时登录
 	SessionsSecret string `json:"sessions_secret" yaml:"sessions_secret"` // 用于加密会话的密钥，确保会话数据的安全性
 	OssType        string `json:"oss_type" yaml:"oss_type"`               // 对应的对象存储服务类型，如 "local" 或 "qiniu"
+	CodeSecret     string `json:"-" yaml:"code_secret"`                   // 计算邮箱验证码 HMAC 的密钥，未设置时使用 sessions_secret
 }
 
 func (s System) Addr() string {
 	return fmt.Sprintf("%s:%d", s.Host, s.Port)
 }
 
+// VerificationSecret 返回计算邮箱验证码 HMAC 的密钥
+func (s System) VerificationSecret() []byte {
+	if s.CodeSecret != "" {
+		return []byte(s.CodeSecret)
+	}
+	return []byte(s.SessionsSecret)
+}
+
 func (s System) Storage() appTypes.Storage {
 	switch strings.ToLower(s.OssType) {
 	case "local", "Local":
@@ -0,0 +1,15 @@
+package config
+
+// TwoFactor 两步验证配置
+type TwoFactor struct {
+	Issuer       string `json:"issuer" yaml:"issuer"`               // 身份验证器中显示的发行方名称，为空时使用网站名称
+	RequireAdmin bool   `json:"require_admin" yaml:"require_admin"` // 是否要求管理员开启两步验证，开启后未启用两步验证的管理员无法访问管理接口
+}
+
+// IssuerName 返回身份验证器中显示的发行方名称
+func (t TwoFactor) IssuerName(website Website) string {
+	if t.Issuer != "" {
+		return t.Issuer
+	}
+	return website.Name
+}
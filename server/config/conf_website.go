@@ -4,6 +4,7 @@ package config
 type Website struct {
 	Logo                 string `json:"logo" yaml:"logo"`
 	FullLogo             string `json:"full_logo" yaml:"full_logo"`
+	URL                  string `json:"url" yaml:"url"`                                       // 网站地址，例如 https://example.com，用于生成订阅源等对外链接
 	Title                string `json:"title" yaml:"title"`                                   // 网站标题
 	Slogan               string `json:"slogan" yaml:"slogan"`                                 // 网站标语
 	SloganEn             string `json:"slogan_en" yaml:"slogan_en"`                           // 英文标语
@@ -1,18 +1,39 @@
 package config
 
 type Config struct {
-	Captcha Captcha `json:"captcha" yaml:"captcha"`
-	Email   Email   `json:"email" yaml:"email"`
-	ES      ES      `json:"es" yaml:"es"`
-	Gaode   Gaode   `json:"gaode" yaml:"gaode"`
-	Jwt     Jwt     `json:"jwt" yaml:"jwt"`
-	Mysql   Mysql   `json:"mysql" yaml:"mysql"`
-	Qiniu   Qiniu   `json:"qiniu" yaml:"qiniu"`
-	QQ      QQ      `json:"qq" yaml:"qq"`
-	Redis   Redis   `json:"redis" yaml:"redis"`
-	System  System  `json:"system" yaml:"system"`
-	Upload  Upload  `json:"upload" yaml:"upload"`
-	Website Website `json:"website" yaml:"website"`
-	Zap     Zap     `json:"zap" yaml:"zap"`
-	AI      Ai      `json:"ai" yaml:"ai"`
+	Backup    Backup    `json:"backup" yaml:"backup"`
+	Captcha   Captcha   `json:"captcha" yaml:"captcha"`
+	Email     Email     `json:"email" yaml:"email"`
+	ES        ES        `json:"es" yaml:"es"`
+	Gaode     Gaode     `json:"gaode" yaml:"gaode"`
+	Jwt       Jwt       `json:"jwt" yaml:"jwt"`
+	Mysql     Mysql     `json:"mysql" yaml:"mysql"`
+	Qiniu     Qiniu     `json:"qiniu" yaml:"qiniu"`
+	QQ        QQ        `json:"qq" yaml:"qq"`
+	Redis     Redis     `json:"redis" yaml:"redis"`
+	Sitemap   Sitemap   `json:"sitemap" yaml:"sitemap"`
+	System    System    `json:"system" yaml:"system"`
+	TwoFactor TwoFactor `json:"two_factor" yaml:"two_factor"`
+	Upload    Upload    `json:"upload" yaml:"upload"`
+	Website   Website   `json:"website" yaml:"website"`
+	Zap       Zap       `json:"zap" yaml:"zap"`
+	AI        Ai        `json:"ai" yaml:"ai"`
+}
+
+// Sanitized 返回去除了密码、密钥等敏感信息的配置副本
+func (c Config) Sanitized() Config {
+	c.Email.Secret = ""
+	c.ES.Password = ""
+	c.Gaode.Key = ""
+	c.Jwt.AccessTokenSecret = ""
+	c.Jwt.RefreshTokenSecret = ""
+	c.Mysql.Password = ""
+	c.Qiniu.AccessKey = ""
+	c.Qiniu.SecretKey = ""
+	c.QQ.AppKey = ""
+	c.Redis.Password = ""
+	c.System.SessionsSecret = ""
+	c.System.CodeSecret = ""
+	c.AI.Key = ""
+	return c
 }
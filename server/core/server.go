@@ -3,7 +3,6 @@ package core
 import (
 	"server/global"
 	"server/initialize"
-	"server/service"
 
 	"go.uber.org/zap"
 )
@@ -17,9 +16,6 @@ func RunServer() {
 	addr := global.Config.System.Addr()
 	Router := initialize.InitRouter()
 
-	// 加载所有的 JWT 黑名单，存入本地缓存
-	service.LoadAll()
-
 	// 初始化服务器并启动
 	s := initServer(addr, Router)
 	global.Log.Info("server run success on ", zap.String("address", addr))
@@ -0,0 +1,43 @@
+package flag
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+)
+
+// readCheckpoint 读取断点文件，文件不存在时返回 false
+func readCheckpoint(path string, v any) (bool, error) {
+	byteData, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if err := json.Unmarshal(byteData, v); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// writeCheckpoint 写入断点文件，先写临时文件再重命名，避免中断时留下不完整的断点
+func writeCheckpoint(path string, v any) error {
+	byteData, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, byteData, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// removeCheckpoint 任务完成后删除断点文件
+func removeCheckpoint(path string) error {
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
@@ -14,15 +14,23 @@ import (
 var (
 	sqlFlag = &cli.BoolFlag{
 		Name:  "sql",
-		Usage: "Initializes the srtucture of the MySQL database table.",
+		Usage: "Initializes or upgrades the structure of the MySQL database tables by applying all pending migrations.",
+	}
+	migrateFlag = &cli.StringFlag{
+		Name:  "migrate",
+		Usage: "Runs database migrations: 'up' applies all pending migrations, 'down' rolls back the last migration, 'status' lists all migrations.",
 	}
 	sqlExportFlag = &cli.BoolFlag{
 		Name:  "sql-export",
-		Usage: "Exports SQL data to a specified file.",
+		Usage: "Exports the structure and data of MySQL tables to a gzip-compressed SQL file.",
 	}
 	sqlImportFlag = &cli.StringFlag{
 		Name:  "sql-import",
-		Usage: "Imports SQL data from a specified file.",
+		Usage: "Imports SQL data from a specified file, which may be gzip-compressed.",
+	}
+	tablesFlag = &cli.StringSliceFlag{
+		Name:  "tables",
+		Usage: "Limits --sql-export and --sql-import to the given comma-separated tables. Can be specified multiple times.",
 	}
 	esFlag = &cli.BoolFlag{
 		Name:  "es",
@@ -30,11 +38,35 @@ var (
 	}
 	esExportFlag = &cli.BoolFlag{
 		Name:  "es-export",
-		Usage: "Exports data from Elasticsearch to a specified file.",
+		Usage: "Exports data from Elasticsearch to a gzip-compressed NDJSON file, resuming an interrupted export if any.",
 	}
 	esImportFlag = &cli.StringFlag{
 		Name:  "es-import",
-		Usage: "Imports data into Elasticsearch from a specified file.",
+		Usage: "Imports data into Elasticsearch from an NDJSON (optionally gzip-compressed) or legacy JSON file, resuming an interrupted import if any.",
+	}
+	esReindexFlag = &cli.BoolFlag{
+		Name:  "es-reindex",
+		Usage: "Rebuilds the Elasticsearch index with the current mapping and switches the alias without downtime.",
+	}
+	esReconcileFlag = &cli.BoolFlag{
+		Name:  "es-reconcile",
+		Usage: "Recomputes the comment and like counts of articles from MySQL and fixes them in Elasticsearch.",
+	}
+	mdImportFlag = &cli.StringSliceFlag{
+		Name:  "md-import",
+		Usage: "Imports articles from Markdown files with front matter, a directory or a zip file. Articles without a status are imported as published. Can be specified multiple times.",
+	}
+	mdExportFlag = &cli.BoolFlag{
+		Name:  "md-export",
+		Usage: "Exports all articles as Markdown files with front matter to a zip file.",
+	}
+	backupFlag = &cli.BoolFlag{
+		Name:  "backup",
+		Usage: "Creates a versioned backup archive of MySQL data, ES articles, uploaded files and the sanitized config.yaml.",
+	}
+	restoreFlag = &cli.StringFlag{
+		Name:  "restore",
+		Usage: "Verifies and restores MySQL data, ES articles and uploaded files from a specified backup archive.",
 	}
 	adminFlag = &cli.BoolFlag{
 		Name:  "admin",
@@ -45,8 +77,12 @@ var (
 // Run 执行基于命令行标志的相应操作
 // 它处理不同的标志，执行相应操作，并记录成功或错误的消息
 func Run(c *cli.Context) {
-	// 检查是否设置了多个标志
-	if c.NumFlags() > 1 {
+	// 检查是否设置了多个标志，--tables 只用于限定其他命令的范围，不计入其中
+	numFlags := c.NumFlags()
+	if c.IsSet(tablesFlag.Name) {
+		numFlags--
+	}
+	if numFlags > 1 {
 		err := cli.NewExitError("Only one command can be specified", 1)
 		global.Log.Error("Invalid command usage:", zap.Error(err))
 		os.Exit(1)
@@ -61,14 +97,20 @@ func Run(c *cli.Context) {
 		} else {
 			global.Log.Info("Successfully created table structure")
 		}
+	case c.IsSet(migrateFlag.Name):
+		if err := Migrate(c.String(migrateFlag.Name)); err != nil {
+			global.Log.Error("Failed to run migrations:", zap.Error(err))
+		} else {
+			global.Log.Info("Successfully ran migrations")
+		}
 	case c.Bool(sqlExportFlag.Name):
-		if err := SQLExport(); err != nil {
+		if path, err := SQLExport(c.StringSlice(tablesFlag.Name)); err != nil {
 			global.Log.Error("Failed to export SQL data:", zap.Error(err))
 		} else {
-			global.Log.Info("Successfully exported SQL data")
+			global.Log.Info("Successfully exported SQL data to " + path)
 		}
 	case c.IsSet(sqlImportFlag.Name):
-		if errs := SQLImport(c.String(sqlImportFlag.Name)); len(errs) > 0 {
+		if errs := SQLImport(c.String(sqlImportFlag.Name), c.StringSlice(tablesFlag.Name)); len(errs) > 0 {
 			var combinedErrors string
 			for _, err := range errs {
 				combinedErrors += err.Error() + "\n"
@@ -85,17 +127,53 @@ func Run(c *cli.Context) {
 			global.Log.Info("Successfully created ES indices")
 		}
 	case c.Bool(esExportFlag.Name):
-		if err := ElasticsearchExport(); err != nil {
-			global.Log.Error("Failed to export ES data:", zap.Error(err))
+		if num, err := ElasticsearchExport(); err != nil {
+			global.Log.Error("Failed to export ES data, run the command again to resume:", zap.Error(err))
 		} else {
-			global.Log.Info("Successfully exported ES data")
+			global.Log.Info(fmt.Sprintf("Successfully exported ES data, totaling %d records", num))
 		}
 	case c.IsSet(esImportFlag.Name):
 		if num, err := ElasticsearchImport(c.String(esImportFlag.Name)); err != nil {
-			global.Log.Error("Failed to import ES data:", zap.Error(err))
+			global.Log.Error("Failed to import ES data, run the command again to resume:", zap.Error(err))
 		} else {
 			global.Log.Info(fmt.Sprintf("Successfully imported ES data, totaling %d records", num))
 		}
+	case c.Bool(esReindexFlag.Name):
+		if num, err := ElasticsearchReindex(); err != nil {
+			global.Log.Error("Failed to reindex ES data:", zap.Error(err))
+		} else {
+			global.Log.Info(fmt.Sprintf("Successfully reindexed ES data, totaling %d records", num))
+		}
+	case c.Bool(esReconcileFlag.Name):
+		if num, err := ElasticsearchReconcile(); err != nil {
+			global.Log.Error("Failed to reconcile ES counters:", zap.Error(err))
+		} else {
+			global.Log.Info(fmt.Sprintf("Successfully reconciled ES counters, %d articles fixed", num))
+		}
+	case c.IsSet(mdImportFlag.Name):
+		num, errs := MarkdownImport(c.StringSlice(mdImportFlag.Name))
+		for _, err := range errs {
+			global.Log.Error("Failed to import Markdown article:", zap.Error(err))
+		}
+		global.Log.Info(fmt.Sprintf("Imported Markdown articles, totaling %d articles, %d failed", num, len(errs)))
+	case c.Bool(mdExportFlag.Name):
+		if err := MarkdownExport(); err != nil {
+			global.Log.Error("Failed to export Markdown articles:", zap.Error(err))
+		} else {
+			global.Log.Info("Successfully exported Markdown articles")
+		}
+	case c.Bool(backupFlag.Name):
+		if path, err := Backup(); err != nil {
+			global.Log.Error("Failed to create backup:", zap.Error(err))
+		} else {
+			global.Log.Info("Successfully created backup " + path)
+		}
+	case c.IsSet(restoreFlag.Name):
+		if err := Restore(c.String(restoreFlag.Name)); err != nil {
+			global.Log.Error("Failed to restore backup:", zap.Error(err))
+		} else {
+			global.Log.Info("Successfully restored backup")
+		}
 	case c.Bool(adminFlag.Name):
 		if err := Admin(); err != nil {
 			global.Log.Error("Failed to create an administrator:", zap.Error(err))
@@ -114,11 +192,19 @@ func NewApp() *cli.App {
 	app.Name = "Go Blog"
 	app.Flags = []cli.Flag{
 		sqlFlag,
+		migrateFlag,
 		sqlExportFlag,
 		sqlImportFlag,
+		tablesFlag,
 		esFlag,
 		esExportFlag,
 		esImportFlag,
+		esReindexFlag,
+		esReconcileFlag,
+		mdImportFlag,
+		mdExportFlag,
+		backupFlag,
+		restoreFlag,
 		adminFlag,
 	}
 	app.Action = Run
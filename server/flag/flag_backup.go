@@ -0,0 +1,235 @@
+package flag
+
+import (
+	"archive/tar"
+	"bufio"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"server/global"
+	"server/model/other"
+	"server/service"
+	"server/utils"
+	"strings"
+	"time"
+)
+
+// Backup 将 MySQL 数据、ES 文章、本地上传的文件以及去除敏感信息的配置打包为一个带清单和校验值的备份文件
+func Backup() (string, error) {
+	archivePath := fmt.Sprintf("backup_%s.tar.gz", time.Now().Format("20060102150405"))
+	if _, err := service.ServiceGroupApp.BackupService.BackupArchive(archivePath); err != nil {
+		os.Remove(archivePath)
+		return "", err
+	}
+	return archivePath, nil
+}
+
+// Restore 从备份文件恢复 MySQL 数据、ES 文章和本地上传的文件，恢复前会校验所有文件的完整性，配置文件不会被恢复
+func Restore(archivePath string) error {
+	dir, err := os.MkdirTemp("", "blog-restore-*")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	// 解压并校验备份包，在此之前不改动任何数据
+	if err := extractBackup(archivePath, dir); err != nil {
+		return err
+	}
+	manifest, err := verifyBackup(dir)
+	if err != nil {
+		return err
+	}
+	global.Log.Info(fmt.Sprintf("Verified backup created at %s with %d tables, %d ES documents and %d files",
+		manifest.CreatedAt, len(manifest.Tables), manifest.Documents, len(manifest.Files)))
+
+	// 打印提示信息，询问是否覆盖现有数据
+	fmt.Println("Restoring will overwrite the current MySQL data, ES articles and uploaded files. Do you want to continue? (y/n)")
+	confirmed := false
+	scanner := bufio.NewScanner(os.Stdin)
+	for !confirmed && scanner.Scan() {
+		switch scanner.Text() {
+		case "y":
+			confirmed = true
+		case "n":
+			return errors.New("restore cancelled")
+		default:
+			fmt.Println("Invalid input. Please enter 'y' to restore the backup, or 'n' to cancel.")
+		}
+	}
+	if !confirmed {
+		return scanner.Err()
+	}
+
+	// 恢复 MySQL 数据
+	sqlFile, err := os.Open(filepath.Join(dir, service.BackupSQLFile))
+	if err != nil {
+		return err
+	}
+	defer sqlFile.Close()
+	sqlDB, err := global.DB.DB()
+	if err != nil {
+		return err
+	}
+	if errs := utils.RestoreSQL(sqlDB, sqlFile, nil); len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+	global.Log.Info("Restored MySQL data")
+
+	// 恢复 ES 文章
+	esFile, err := os.Open(filepath.Join(dir, service.BackupESFile))
+	if err != nil {
+		return err
+	}
+	defer esFile.Close()
+	next, closeFn, err := esDocumentReader(esFile)
+	if err != nil {
+		return err
+	}
+	defer closeFn()
+	if err := esRecreateIndex(); err != nil {
+		return err
+	}
+	if _, err := esImportDocuments(next, 0, nil); err != nil {
+		return err
+	}
+
+	// 恢复本地上传的文件
+	uploads := 0
+	for _, file := range manifest.Files {
+		rel, ok := strings.CutPrefix(file.Name, service.BackupUploadsDir+"/")
+		if !ok {
+			continue
+		}
+		target := filepath.Join(global.Config.Upload.Path, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(target), os.ModePerm); err != nil {
+			return err
+		}
+		if err := copyFile(filepath.Join(dir, filepath.FromSlash(file.Name)), target); err != nil {
+			return err
+		}
+		uploads++
+	}
+	global.Log.Info(fmt.Sprintf("Restored %d uploaded files", uploads))
+	return nil
+}
+
+// extractBackup 将备份包解压到指定目录
+func extractBackup(archivePath, dir string) error {
+	archive, err := os.Open(archivePath)
+	if err != nil {
+		return err
+	}
+	defer archive.Close()
+
+	gz, err := gzip.NewReader(archive)
+	if err != nil {
+		return err
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		// 拒绝指向目录之外的路径
+		if !filepath.IsLocal(filepath.FromSlash(header.Name)) {
+			return fmt.Errorf("invalid file path in backup: %s", header.Name)
+		}
+		target := filepath.Join(dir, filepath.FromSlash(header.Name))
+		if err := os.MkdirAll(filepath.Dir(target), os.ModePerm); err != nil {
+			return err
+		}
+		err = utils.WriteFile(target, func(w io.Writer) error {
+			_, err := io.Copy(w, tr)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// verifyBackup 读取清单并校验备份包中每个文件的大小和校验值
+func verifyBackup(dir string) (other.BackupManifest, error) {
+	var manifest other.BackupManifest
+	manifestData, err := os.ReadFile(filepath.Join(dir, service.BackupManifestFile))
+	if err != nil {
+		return manifest, fmt.Errorf("failed to read backup manifest: %w", err)
+	}
+	if err := json.Unmarshal(manifestData, &manifest); err != nil {
+		return manifest, err
+	}
+	if manifest.Version <= 0 || manifest.Version > service.BackupVersion {
+		return manifest, fmt.Errorf("unsupported backup version %d", manifest.Version)
+	}
+
+	names := make(map[string]bool, len(manifest.Files))
+	for _, file := range manifest.Files {
+		names[file.Name] = true
+		localPath := filepath.Join(dir, filepath.FromSlash(file.Name))
+		info, err := os.Stat(localPath)
+		if err != nil {
+			return manifest, fmt.Errorf("missing file %s in backup", file.Name)
+		}
+		if info.Size() != file.Size {
+			return manifest, fmt.Errorf("size mismatch for %s in backup", file.Name)
+		}
+		checksum, err := fileSHA256(localPath)
+		if err != nil {
+			return manifest, err
+		}
+		if checksum != file.SHA256 {
+			return manifest, fmt.Errorf("checksum mismatch for %s in backup", file.Name)
+		}
+	}
+	for _, name := range []string{service.BackupSQLFile, service.BackupESFile} {
+		if !names[name] {
+			return manifest, fmt.Errorf("missing file %s in backup", name)
+		}
+	}
+	return manifest, nil
+}
+
+// fileSHA256 计算文件的 SHA-256 校验值
+func fileSHA256(name string) (string, error) {
+	file, err := os.Open(name)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// copyFile 复制文件
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	return utils.WriteFile(dst, func(w io.Writer) error {
+		_, err := io.Copy(w, in)
+		return err
+	})
+}
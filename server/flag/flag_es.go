@@ -31,7 +31,7 @@ func Elasticsearch() error {
 		case "y":
 			// 如果用户输入 y，删除索引
 			fmt.Println("Proceeding to delete the data and recreate the index...")
-			if err := esService.IndexDelete(elasticsearch.ArticleIndex()); err != nil {
+			if err := esService.IndexDeleteWithAlias(elasticsearch.ArticleIndex()); err != nil {
 				return err
 			}
 		case "n":
@@ -45,6 +45,7 @@ func Elasticsearch() error {
 		}
 	}
 
-	// 创建索引
-	return esService.IndexCreate(elasticsearch.ArticleIndex(), elasticsearch.ArticleMapping())
+	// 创建带版本号的索引，通过别名访问
+	_, err = esService.IndexCreateWithAlias(elasticsearch.ArticleIndex(), elasticsearch.ArticleMapping())
+	return err
 }
@@ -1,98 +1,106 @@
 package flag
 
 import (
+	"compress/gzip"
 	"context"
-	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"server/global"
 	"server/model/elasticsearch"
 	"server/model/other"
+	"server/service"
+	"server/utils"
 	"time"
-
-	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
 )
 
-// ElasticsearchExport 导出 ES 中的数据到 JSON 文件
-func ElasticsearchExport() error {
-	// 声明变量用于存储响应结果
-	var response other.ESIndexResponse
+// esExportCheckpointFile 导出中断后用于续传的断点文件
+const esExportCheckpointFile = "es_export.checkpoint"
 
-	// 发起第一次搜索请求，设置查询条件：索引名称、滚动时间（1分钟）、每次返回的文档数量、查询条件（匹配所有文档）
-	res, err := global.ESClient.Search().
-		Index(elasticsearch.ArticleIndex()).                   // 设置查询的索引名称
-		Scroll("1m").                                          // 设置滚动时间为 1 分钟
-		Size(1000).                                            // 每次查询返回 1000 条数据
-		Query(&types.Query{MatchAll: &types.MatchAllQuery{}}). // 查询条件：匹配所有文档
-		Do(context.TODO())                                     // 执行请求，传入空的 context
-	if err != nil {
-		return err
-	}
+// esExportCheckpoint ES 导出断点
+type esExportCheckpoint struct {
+	File   string `json:"file"`   // 导出文件名
+	Offset int64  `json:"offset"` // 已完整写入的文件字节数
+	Total  int    `json:"total"`  // 已导出的文档数量
+	other.EsPosition
+}
 
-	// 遍历第一次查询结果的文档
-	for _, hit := range res.Hits.Hits {
-		// 为每个文档创建一个 Data 结构体，并将其 ID 和 Source（文档内容）存储
-		data := other.Data{
-			ID:  hit.Id_,
-			Doc: hit.Source_,
-		}
-		// 将数据追加到 response 的 Data 字段中
-		response.Data = append(response.Data, data)
+// ElasticsearchExport 以 NDJSON 格式流式导出 ES 中的数据到 gzip 文件，支持中断后续传
+func ElasticsearchExport() (int, error) {
+	var checkpoint esExportCheckpoint
+	resumed, err := readCheckpoint(esExportCheckpointFile, &checkpoint)
+	if err != nil {
+		return 0, err
 	}
 
-	// 使用 Scroll API 进行后续的滚动查询，直到没有更多数据
-	for {
-		// 发起新的滚动查询，传入上一个查询返回的 ScrollId
-		res, err := global.ESClient.Scroll().ScrollId(*res.ScrollId_).Scroll("1m").Do(context.TODO())
+	var file *os.File
+	if resumed {
+		// 丢弃上次中断时未完整写入的数据，从断点处继续写入
+		file, err = os.OpenFile(checkpoint.File, os.O_WRONLY, 0644)
 		if err != nil {
-			// 如果请求出错，返回错误
-			return err
+			return 0, err
 		}
-
-		// 如果没有更多数据，结束滚动查询
-		if len(res.Hits.Hits) == 0 {
-			break
+		if err := file.Truncate(checkpoint.Offset); err != nil {
+			file.Close()
+			return 0, err
 		}
-
-		// 遍历滚动查询结果中的文档
-		for _, hit := range res.Hits.Hits {
-			// 为每个文档创建一个 Data 结构体，并将其 ID 和 Source 存储
-			data := other.Data{
-				ID:  hit.Id_,
-				Doc: hit.Source_,
-			}
-			// 将数据追加到 response 的 Data 字段中
-			response.Data = append(response.Data, data)
+		if _, err := file.Seek(checkpoint.Offset, io.SeekStart); err != nil {
+			file.Close()
+			return 0, err
+		}
+		global.Log.Info(fmt.Sprintf("Resuming ES export to %s after %d documents", checkpoint.File, checkpoint.Total))
+	} else {
+		// 生成文件名，格式为 "es_yyyyMMdd.ndjson.gz"
+		checkpoint.File = fmt.Sprintf("es_%s.ndjson.gz", time.Now().Format("20060102"))
+		file, err = os.Create(checkpoint.File)
+		if err != nil {
+			return 0, err
 		}
 	}
+	defer file.Close()
 
-	// 清除滚动查询，释放 Elasticsearch 上的资源
-	_, err = global.ESClient.ClearScroll().ScrollId(*res.ScrollId_).Do(context.TODO())
+	countRes, err := global.ESClient.Count().Index(elasticsearch.ArticleIndex()).Do(context.TODO())
 	if err != nil {
-		return err
+		return 0, err
 	}
 
-	// 生成文件名，格式为 "es_yyyyMMdd.json"
-	fileName := fmt.Sprintf("es_%s.json", time.Now().Format("20060102"))
-
-	// 创建文件
-	file, err := os.Create(fileName)
-	if err != nil {
-		return err
-	}
-	defer file.Close()
+	esService := service.ServiceGroupApp.EsService
+	err = esService.ScanDocuments(elasticsearch.ArticleIndex(), &checkpoint.EsPosition, func(batch []other.Data, position other.EsPosition) error {
+		// 每批数据写成一个独立的 gzip 成员，保证断点之前的文件内容始终完整可读
+		gz := gzip.NewWriter(file)
+		if err := utils.WriteNDJSON(gz, batch); err != nil {
+			return err
+		}
+		if err := gz.Close(); err != nil {
+			return err
+		}
+		if err := file.Sync(); err != nil {
+			return err
+		}
 
-	// 将 response 数据结构转换为 JSON 格式的字节数据
-	byteData, err := json.Marshal(response)
+		// 记录本批之后的断点
+		offset, err := file.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return err
+		}
+		next := checkpoint
+		next.Offset = offset
+		next.Total += len(batch)
+		next.EsPosition = position
+		if err := writeCheckpoint(esExportCheckpointFile, next); err != nil {
+			return err
+		}
+		checkpoint.Offset, checkpoint.Total = next.Offset, next.Total
+		global.Log.Info(fmt.Sprintf("Exported %d/%d ES documents", checkpoint.Total, countRes.Count))
+		return nil
+	})
 	if err != nil {
-		return err
+		return checkpoint.Total, err
 	}
 
-	// 将 JSON 数据写入文件
-	_, err = file.Write(byteData)
-	if err != nil {
-		return err
+	if err := removeCheckpoint(esExportCheckpointFile); err != nil {
+		return checkpoint.Total, err
 	}
-
-	return nil
+	global.Log.Info(fmt.Sprintf("ES data exported to %s", checkpoint.File))
+	return checkpoint.Total, nil
 }
@@ -1,8 +1,11 @@
 package flag
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
+	"io"
 	"os"
 	"server/global"
 	"server/model/elasticsearch"
@@ -11,60 +14,208 @@ import (
 
 	"github.com/elastic/go-elasticsearch/v8/typedapi/core/bulk"
 	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
-	"github.com/elastic/go-elasticsearch/v8/typedapi/types/enums/refresh"
 )
 
-// ElasticsearchImport 从指定的 JSON 文件导入数据到 ES
-func ElasticsearchImport(jsonPath string) (int, error) {
-	// 读取指定路径的 JSON 文件
-	byteData, err := os.ReadFile(jsonPath)
+// esImportCheckpoint ES 导入断点
+type esImportCheckpoint struct {
+	Total int `json:"total"` // 已成功导入的文档数量
+}
+
+// ElasticsearchImport 从指定文件流式导入数据到 ES，支持 NDJSON、gzip 压缩以及旧版 JSON 格式，中断后再次执行会从断点继续
+func ElasticsearchImport(path string) (int, error) {
+	checkpointPath := path + ".checkpoint"
+	var checkpoint esImportCheckpoint
+	resumed, err := readCheckpoint(checkpointPath, &checkpoint)
 	if err != nil {
 		return 0, err
 	}
 
-	// 反序列化 JSON 数据到 ESIndexResponse 结构体
-	var response other.ESIndexResponse
-	err = json.Unmarshal(byteData, &response)
+	file, err := os.Open(path)
 	if err != nil {
 		return 0, err
 	}
+	defer file.Close()
+
+	next, closeFn, err := esDocumentReader(file)
+	if err != nil {
+		return 0, err
+	}
+	defer closeFn()
+
+	if resumed {
+		// 续传时保留已导入的数据，跳过已经成功导入的文档
+		global.Log.Info(fmt.Sprintf("Resuming ES import from %s after %d documents", path, checkpoint.Total))
+		for i := 0; i < checkpoint.Total; i++ {
+			if _, err := next(); err != nil {
+				return 0, err
+			}
+		}
+	} else {
+		if err := esRecreateIndex(); err != nil {
+			return 0, err
+		}
+	}
+
+	total, err := esImportDocuments(next, checkpoint.Total, func(total int) error {
+		// 记录断点
+		return writeCheckpoint(checkpointPath, esImportCheckpoint{Total: total})
+	})
+	if err != nil {
+		return total, err
+	}
 
-	// 创建 Elasticsearch 索引
+	if err := removeCheckpoint(checkpointPath); err != nil {
+		return total, err
+	}
+	return total, nil
+}
+
+// esRecreateIndex 删除并重新创建文章索引
+func esRecreateIndex() error {
 	esService := service.ServiceGroupApp.EsService
 	indexExists, err := esService.IndexExists(elasticsearch.ArticleIndex())
 	if err != nil {
-		return 0, err
+		return err
 	}
 	if indexExists {
-		if err := esService.IndexDelete(elasticsearch.ArticleIndex()); err != nil {
-			return 0, err
+		if err := esService.IndexDeleteWithAlias(elasticsearch.ArticleIndex()); err != nil {
+			return err
 		}
 	}
-	err = esService.IndexCreate(elasticsearch.ArticleIndex(), elasticsearch.ArticleMapping())
+	_, err = esService.IndexCreateWithAlias(elasticsearch.ArticleIndex(), elasticsearch.ArticleMapping())
+	return err
+}
+
+// esImportDocuments 分批导入 next 读取的所有文档，刷新索引并补全缺少的 slug 和搜索建议，total 为此前已导入的数量，每批成功后调用 onBatch
+func esImportDocuments(next func() (other.Data, error), total int, onBatch func(total int) error) (int, error) {
+	batchSize := global.Config.ES.Batch()
+	for {
+		// 构建批量请求数据
+		var request bulk.Request
+		count := 0
+		for count < batchSize {
+			data, err := next()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return total, err
+			}
+			request = append(request, types.OperationContainer{Index: &types.IndexOperation{Id_: data.ID}}, data.Doc)
+			count++
+		}
+		if count == 0 {
+			break
+		}
+
+		if err := esBulk(&request); err != nil {
+			return total, err
+		}
+		total += count
+		if onBatch != nil {
+			if err := onBatch(total); err != nil {
+				return total, err
+			}
+		}
+		global.Log.Info(fmt.Sprintf("Imported %d ES documents", total))
+	}
+
+	// 刷新索引以使文档立即可见
+	if _, err := global.ESClient.Indices.Refresh().Index(elasticsearch.ArticleIndex()).Do(context.TODO()); err != nil {
+		return total, err
+	}
+
+	// 旧版本导出的文章没有 slug 和搜索建议，导入后补全
+	num, err := service.ServiceGroupApp.ArticleService.ArticleBackfillSlugs()
 	if err != nil {
-		return 0, err
+		return total, err
+	}
+	if num > 0 {
+		global.Log.Info(fmt.Sprintf("Generated slugs for %d imported articles", num))
 	}
+	num, err = service.ServiceGroupApp.ArticleService.ArticleBackfillSuggest()
+	if err != nil {
+		return total, err
+	}
+	if num > 0 {
+		global.Log.Info(fmt.Sprintf("Generated search suggestions for %d imported articles", num))
+	}
+	return total, nil
+}
 
-	// 构建批量请求数据
-	var request bulk.Request
-	for _, data := range response.Data {
-		// 为每条数据创建索引操作，指定文档的 ID
-		request = append(request, types.OperationContainer{Index: &types.IndexOperation{Id_: data.ID}})
-		// 添加文档数据到请求
-		request = append(request, data.Doc)
+// esDocumentReader 返回逐条读取导出文件中文档的函数，读取完毕时返回 io.EOF
+func esDocumentReader(r io.Reader) (func() (other.Data, error), func(), error) {
+	br, closeFn, err := gzipReader(r)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	// 使用 Elasticsearch 客户端执行批量操作
-	_, err = global.ESClient.Bulk().
-		Request(&request).                   // 提交请求数据
+	// 兼容旧版导出的 {"data":[...]} 格式
+	if prefix, _ := br.Peek(8); bytes.HasPrefix(prefix, []byte(`{"data"`)) {
+		var response other.ESIndexResponse
+		if err := json.NewDecoder(br).Decode(&response); err != nil {
+			closeFn()
+			return nil, nil, err
+		}
+		i := 0
+		return func() (other.Data, error) {
+			if i >= len(response.Data) {
+				return other.Data{}, io.EOF
+			}
+			i++
+			return response.Data[i-1], nil
+		}, closeFn, nil
+	}
+
+	return func() (other.Data, error) {
+		for {
+			line, err := br.ReadBytes('\n')
+			if err != nil && err != io.EOF {
+				return other.Data{}, err
+			}
+			line = bytes.TrimSpace(line)
+			if len(line) == 0 {
+				if err == io.EOF {
+					return other.Data{}, io.EOF
+				}
+				continue
+			}
+			var data other.Data
+			if err := json.Unmarshal(line, &data); err != nil {
+				return other.Data{}, err
+			}
+			return data, nil
+		}
+	}, closeFn, nil
+}
+
+// esBulk 执行批量请求，任一文档失败时返回错误
+func esBulk(request *bulk.Request) error {
+	res, err := global.ESClient.Bulk().
+		Request(request).                    // 提交请求数据
 		Index(elasticsearch.ArticleIndex()). // 指定索引名称
-		Refresh(refresh.True).               // 强制刷新索引以使文档立即可见
 		Do(context.TODO())                   // 执行请求
 	if err != nil {
-		return 0, err
+		return err
 	}
-
-	// 返回导入的数据总条数
-	total := len(response.Data)
-	return total, nil
+	if !res.Errors {
+		return nil
+	}
+	for _, item := range res.Items {
+		for _, result := range item {
+			if result.Error == nil {
+				continue
+			}
+			reason := result.Error.Type
+			if result.Error.Reason != nil {
+				reason = *result.Error.Reason
+			}
+			id := ""
+			if result.Id_ != nil {
+				id = *result.Id_
+			}
+			return fmt.Errorf("failed to import document %s: %s", id, reason)
+		}
+	}
+	return nil
 }
@@ -0,0 +1,8 @@
+package flag
+
+import "server/service"
+
+// ElasticsearchReconcile 根据 MySQL 中的评论和收藏重新计算 ES 中文章的 comments 和 likes
+func ElasticsearchReconcile() (int, error) {
+	return service.ServiceGroupApp.ArticleService.ReconcileCounters()
+}
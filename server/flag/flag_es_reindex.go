@@ -0,0 +1,49 @@
+package flag
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"server/global"
+	"server/model/elasticsearch"
+	"server/service"
+	"strings"
+)
+
+// ElasticsearchReindex 使用当前的映射重建 ES 索引，复制数据后将别名切换到新的索引
+func ElasticsearchReindex() (int64, error) {
+	esService := service.ServiceGroupApp.EsService
+
+	newIndex, oldIndices, total, err := esService.Reindex(elasticsearch.ArticleIndex(), elasticsearch.ArticleMapping())
+	if err != nil {
+		return 0, err
+	}
+	global.Log.Info(fmt.Sprintf("The alias %s now points to the index %s", elasticsearch.ArticleIndex(), newIndex))
+	if len(oldIndices) == 0 {
+		return total, nil
+	}
+
+	// 打印提示信息，询问是否删除旧索引，保留旧索引可以用于回滚
+	fmt.Printf("Do you want to delete the old index %s? (y/n)\n", strings.Join(oldIndices, ", "))
+
+	// 读取用户输入
+	scanner := bufio.NewScanner(os.Stdin)
+	for scanner.Scan() {
+		switch scanner.Text() {
+		case "y":
+			for _, index := range oldIndices {
+				if err := esService.IndexDelete(index); err != nil {
+					return total, err
+				}
+			}
+			fmt.Println("The old index has been deleted.")
+			return total, nil
+		case "n":
+			fmt.Println("The old index is kept and remains read-only.")
+			return total, nil
+		default:
+			fmt.Println("Invalid input. Please enter 'y' to delete the old index, or 'n' to keep it.")
+		}
+	}
+	return total, scanner.Err()
+}
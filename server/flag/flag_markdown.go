@@ -0,0 +1,88 @@
+package flag
+
+import (
+	"archive/zip"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"server/service"
+	"strings"
+	"time"
+)
+
+// MarkdownImport 导入 Markdown 文章，参数可以是 .md 文件、包含 .md 文件的目录或 zip 压缩包
+func MarkdownImport(paths []string) (int, []error) {
+	articleService := service.ServiceGroupApp.ArticleService
+
+	var total int
+	var errs []error
+	for _, p := range paths {
+		info, err := os.Stat(p)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		var num int
+		var importErrs []error
+		switch {
+		case info.IsDir():
+			// 导入目录下的所有 Markdown 文件，图片路径相对于该目录解析
+			fsys := os.DirFS(p)
+			var names []string
+			if err := fs.WalkDir(fsys, ".", func(name string, d fs.DirEntry, err error) error {
+				if err != nil {
+					return err
+				}
+				if !d.IsDir() && isMarkdownFile(name) {
+					names = append(names, name)
+				}
+				return nil
+			}); err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			num, importErrs = articleService.ArticleImport(func(name string) ([]byte, error) {
+				return fs.ReadFile(fsys, name)
+			}, names)
+		case strings.EqualFold(filepath.Ext(p), ".zip"):
+			zr, err := zip.OpenReader(p)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			num, importErrs = articleService.ArticleImportZip(&zr.Reader)
+			_ = zr.Close()
+		default:
+			// 单个 Markdown 文件，图片路径相对于文件所在目录解析
+			dir := filepath.Dir(p)
+			num, importErrs = articleService.ArticleImport(func(name string) ([]byte, error) {
+				return os.ReadFile(filepath.Join(dir, filepath.FromSlash(name)))
+			}, []string{filepath.Base(p)})
+		}
+		total += num
+		errs = append(errs, importErrs...)
+	}
+	return total, errs
+}
+
+// MarkdownExport 将所有文章导出为 Markdown 文件的 zip 压缩包
+func MarkdownExport() error {
+	// 生成文件名，格式为 "articles_yyyyMMdd.zip"
+	fileName := fmt.Sprintf("articles_%s.zip", time.Now().Format("20060102"))
+
+	file, err := os.Create(fileName)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return service.ServiceGroupApp.ArticleService.ArticleExport(file)
+}
+
+// isMarkdownFile 判断文件是否为 Markdown 文件
+func isMarkdownFile(name string) bool {
+	ext := strings.ToLower(filepath.Ext(name))
+	return ext == ".md" || ext == ".markdown"
+}
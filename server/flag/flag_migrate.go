@@ -0,0 +1,73 @@
+package flag
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"server/migration"
+)
+
+// Migrate 执行数据库迁移命令，action 可以是 up、down 或 status
+func Migrate(action string) error {
+	switch action {
+	case "up":
+		return SQL()
+	case "down":
+		return migrateDown()
+	case "status":
+		return migrateStatus()
+	default:
+		return fmt.Errorf("unknown migrate action %s, expected up, down or status", action)
+	}
+}
+
+// migrateDown 确认后回滚最近一次执行的迁移
+func migrateDown() error {
+	last, ok, err := migration.Last()
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errors.New("no migration to roll back")
+	}
+
+	// 打印提示信息，询问是否回滚
+	fmt.Printf("Do you want to roll back the migration %d_%s? (y/n)\n", last.Version, last.Name)
+
+	// 读取用户输入
+	scanner := bufio.NewScanner(os.Stdin)
+	for scanner.Scan() {
+		switch scanner.Text() {
+		case "y":
+			m, err := migration.Down()
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Rolled back migration %d_%s\n", m.Version, m.Name)
+			return nil
+		case "n":
+			return errors.New("rollback cancelled")
+		default:
+			fmt.Println("Invalid input. Please enter 'y' to roll back the migration, or 'n' to cancel.")
+		}
+	}
+	return scanner.Err()
+}
+
+// migrateStatus 打印所有迁移的执行状态
+func migrateStatus() error {
+	list, err := migration.StatusList()
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%-8s %-40s %s\n", "VERSION", "NAME", "APPLIED AT")
+	for _, status := range list {
+		appliedAt := "pending"
+		if status.Applied {
+			appliedAt = status.AppliedAt.Format("2006-01-02 15:04:05")
+		}
+		fmt.Printf("%-8d %-40s %s\n", status.Version, status.Name, appliedAt)
+	}
+	return nil
+}
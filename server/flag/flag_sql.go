@@ -1,24 +1,16 @@
 package flag
 
 import (
+	"fmt"
 	"server/global"
-	"server/model/database"
+	"server/migration"
 )
 
-// SQL 表结构迁移，如果表不存在，它会创建新表；如果表已经存在，它会根据结构更新表
+// SQL 执行所有未执行的数据库迁移，用于初始化或升级表结构
 func SQL() error {
-	return global.DB.Set("gorm:table_options", "ENGINE=InnoDB").AutoMigrate(
-		&database.Advertisement{},
-		&database.ArticleCategory{},
-		&database.ArticleLike{},
-		&database.ArticleTag{},
-		&database.Comment{},
-		&database.Feedback{},
-		&database.FooterLink{},
-		&database.FriendLink{},
-		&database.Image{},
-		&database.JwtBlacklist{},
-		&database.Login{},
-		&database.User{},
-	)
+	done, err := migration.Up()
+	for _, m := range done {
+		global.Log.Info(fmt.Sprintf("Applied migration %d_%s", m.Version, m.Name))
+	}
+	return err
 }
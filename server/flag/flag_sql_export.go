@@ -1,27 +1,69 @@
 package flag
 
 import (
+	"compress/gzip"
 	"fmt"
 	"os"
-	"os/exec"
 	"server/global"
+	"server/service"
+	"server/utils"
+	"slices"
+	"strings"
 	"time"
 )
 
-// SQLExport 导出 MySQL 数据
-func SQLExport() error {
-	mysql := global.Config.Mysql
+// SQLExport 导出 MySQL 数据为 gzip 压缩的 SQL 文件，tables 为空时导出所有表
+func SQLExport(tables []string) (string, error) {
+	tables, err := selectSQLTables(tables)
+	if err != nil {
+		return "", err
+	}
 
 	timer := time.Now().Format("20060102")
-	sqlPath := fmt.Sprintf("mysql_%s.sql", timer)
-	cmd := exec.Command("docker", "exec", "mysql", "mysqldump", "-u"+mysql.Username, "-p"+mysql.Password, mysql.DBName)
+	sqlPath := fmt.Sprintf("mysql_%s.sql.gz", timer)
 
 	outFile, err := os.Create(sqlPath)
 	if err != nil {
-		return err
+		return "", err
 	}
 	defer outFile.Close()
 
-	cmd.Stdout = outFile
-	return cmd.Run()
+	sqlDB, err := global.DB.DB()
+	if err != nil {
+		return "", err
+	}
+
+	gz := gzip.NewWriter(outFile)
+	if err := utils.DumpSQL(sqlDB, gz, tables); err != nil {
+		return "", err
+	}
+	if err := gz.Close(); err != nil {
+		return "", err
+	}
+	return sqlPath, outFile.Sync()
+}
+
+// selectSQLTables 校验指定的表名，未指定时返回所有表
+func selectSQLTables(names []string) ([]string, error) {
+	tables, err := service.ServiceGroupApp.BackupService.Tables()
+	if err != nil {
+		return nil, err
+	}
+	var selected []string
+	for _, name := range names {
+		for _, table := range strings.Split(name, ",") {
+			table = strings.TrimSpace(table)
+			if table == "" {
+				continue
+			}
+			if !slices.Contains(tables, table) {
+				return nil, fmt.Errorf("unknown table %s", table)
+			}
+			selected = append(selected, table)
+		}
+	}
+	if len(selected) == 0 {
+		return tables, nil
+	}
+	return selected, nil
 }
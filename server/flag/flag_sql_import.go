@@ -3,29 +3,33 @@ package flag
 import (
 	"os"
 	"server/global"
-	"strings"
+	"server/utils"
 )
 
-// SQLImport 导入 MySQL 数据
-func SQLImport(sqlPath string) (errs []error) {
-	byteData, err := os.ReadFile(sqlPath)
+// SQLImport 导入 MySQL 数据，支持 gzip 压缩的 SQL 文件，tables 不为空时只导入指定的表
+func SQLImport(sqlPath string, tables []string) (errs []error) {
+	if len(tables) > 0 {
+		var err error
+		if tables, err = selectSQLTables(tables); err != nil {
+			return append(errs, err)
+		}
+	}
+
+	file, err := os.Open(sqlPath)
 	if err != nil {
 		return append(errs, err)
 	}
-	// 分割数据
-	sqlList := strings.Split(string(byteData), ";")
-	for _, sql := range sqlList {
-		// 去除字符串开头和结尾的空白符
-		sql = strings.TrimSpace(sql)
-		if sql == "" {
-			continue
-		}
-		// 执行sql语句
-		err = global.DB.Exec(sql).Error
-		if err != nil {
-			errs = append(errs, err)
-			continue
-		}
+	defer file.Close()
+
+	r, closeFn, err := gzipReader(file)
+	if err != nil {
+		return append(errs, err)
+	}
+	defer closeFn()
+
+	sqlDB, err := global.DB.DB()
+	if err != nil {
+		return append(errs, err)
 	}
-	return nil
+	return utils.RestoreSQL(sqlDB, r, tables)
 }
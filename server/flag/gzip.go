@@ -0,0 +1,20 @@
+package flag
+
+import (
+	"bufio"
+	"compress/gzip"
+	"io"
+)
+
+// gzipReader 根据文件头识别 gzip 压缩数据，返回解压后的读取器，未压缩的数据原样返回
+func gzipReader(r io.Reader) (*bufio.Reader, func(), error) {
+	br := bufio.NewReader(r)
+	if magic, _ := br.Peek(2); len(magic) == 2 && magic[0] == 0x1f && magic[1] == 0x8b {
+		gz, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, nil, err
+		}
+		return bufio.NewReader(gz), func() { gz.Close() }, nil
+	}
+	return br, func() {}, nil
+}
@@ -3,6 +3,8 @@ package initialize
 import (
 	"os"
 	"server/global"
+	esmodel "server/model/elasticsearch"
+	"server/service"
 
 	"github.com/elastic/elastic-transport-go/v8/elastictransport"
 	"github.com/elastic/go-elasticsearch/v8"
@@ -36,3 +38,11 @@ func ConnectEs() *elasticsearch.TypedClient {
 
 	return client
 }
+
+// InitEsMapping 将文章映射中新增的字段同步到已有的索引，使升级后的查询和排序能够使用这些字段
+func InitEsMapping() {
+	err := service.ServiceGroupApp.EsService.IndexPutMapping(esmodel.ArticleIndex(), esmodel.ArticleMapping())
+	if err != nil {
+		global.Log.Error("Failed to update the Elasticsearch mapping, please run --es-reindex to rebuild the index", zap.Error(err))
+	}
+}
@@ -42,6 +42,8 @@ func InitRouter() *gin.Engine {
 		routerGroup.InitArticleRouter(privateGroup, publicGroup, adminGroup)
 		routerGroup.InitCommentRouter(privateGroup, publicGroup, adminGroup)
 		routerGroup.InitFeedbackRouter(privateGroup, publicGroup, adminGroup)
+		routerGroup.InitSeriesRouter(adminGroup, publicGroup)
+		routerGroup.InitTwoFactorRouter(privateGroup)
 	}
 	{
 		routerGroup.InitImageRouter(adminGroup)
@@ -49,9 +51,13 @@ func InitRouter() *gin.Engine {
 		routerGroup.InitFriendLinkRouter(adminGroup, publicGroup)
 		routerGroup.InitWebsiteRouter(adminGroup, publicGroup)
 		routerGroup.InitConfigRouter(adminGroup)
+		routerGroup.InitBackupRouter(adminGroup)
 	}
 	{
 		routerGroup.InitAIRouter(publicGroup)
 	}
+	{
+		routerGroup.InitSitemapRouter(Router.Group(""))
+	}
 	return Router
 }
@@ -14,6 +14,7 @@ func main() {
 	global.DB = initialize.InitGorm()
 	global.Redis = initialize.ConnectRedis()
 	global.ESClient = initialize.ConnectEs()
+	initialize.InitEsMapping()
 
 	defer global.Redis.Close()
 
@@ -3,11 +3,14 @@ package middleware
 import (
 	"server/model/appTypes"
 	"server/model/response"
+	"server/service"
 	"server/utils"
 
 	"github.com/gin-gonic/gin"
 )
 
+var twoFactorService = service.ServiceGroupApp.TwoFactorService
+
 func AdminAuth() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		roleID := utils.GetRoleID(c)
@@ -18,6 +21,16 @@ func AdminAuth() gin.HandlerFunc {
 			return
 		}
 
+		// 要求管理员开启两步验证时，未启用两步验证的管理员无法访问管理接口
+		if twoFactorService.TwoFactorRequired(roleID) {
+			enabled, err := twoFactorService.TwoFactorEnabled(utils.GetUserID(c))
+			if err != nil || !enabled {
+				response.Forbidden("Access denied. Two-factor authentication is required for administrators", c)
+				c.Abort()
+				return
+			}
+		}
+
 		c.Next()
 	}
 }
@@ -9,24 +9,19 @@ import (
 	"server/service"
 	"server/utils"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
 )
 
-var jwtService = service.ServiceGroupApp.JwtService
+var sessionService = service.ServiceGroupApp.SessionService
 
 func JWTAuth() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		accessToken := utils.GetAccessToken(c)
 		refreshToken := utils.GetRefreshToken(c)
 
-		if jwtService.IsInBlacklist(refreshToken) {
-			utils.ClearRefreshToken(c)
-			response.NoAuth("Account logged in from another location or token is invalid", c)
-			c.Abort()
-			return
-		}
-
 		j := utils.NewJWT()
 
 		claims, err := j.ParseAccessToken(accessToken)
@@ -41,17 +36,48 @@ func JWTAuth() gin.HandlerFunc {
 				}
 
 				var user database.User
-				if err := global.DB.Select("uuid", "role_id").Take(&user, refreshClaims.UserID).Error; err != nil {
+				if err := global.DB.Select("uuid", "role_id", "freeze").Take(&user, refreshClaims.UserID).Error; err != nil {
 					utils.ClearRefreshToken(c)
 					response.NoAuth("The user does not exist", c)
 					c.Abort()
 					return
 				}
 
+				// 冻结或已注销的用户不再签发新的访问令牌
+				if user.Freeze {
+					utils.ClearRefreshToken(c)
+					response.NoAuth("The user is frozen, contact the administrator", c)
+					c.Abort()
+					return
+				}
+
+				// 轮换刷新令牌，刷新令牌必须是所属会话当前的令牌，已使用过的令牌会导致整个会话被注销
+				newRefreshClaims := j.RotateRefreshClaims(*refreshClaims)
+				newRefreshToken, err := j.CreateRefreshToken(newRefreshClaims)
+				if err != nil {
+					response.NoAuth("Faild to create new refresh token", c)
+					c.Abort()
+					return
+				}
+				rotated, err := sessionService.SessionRotate(refreshClaims.SessionID, refreshToken, newRefreshToken, c.ClientIP())
+				if err != nil {
+					if !errors.Is(err, service.ErrSessionInvalid) && !errors.Is(err, service.ErrRefreshTokenReused) {
+						global.Log.Error("Failed to rotate refresh token:", zap.Error(err))
+					}
+					utils.ClearRefreshToken(c)
+					response.NoAuth("Session expired or revoked, please log in again", c)
+					c.Abort()
+					return
+				}
+				if rotated {
+					utils.SetRefreshToken(c, newRefreshToken, int(newRefreshClaims.ExpiresAt.Unix()-time.Now().Unix()))
+				}
+
 				newAccessClaims := j.CreateAccessClaims(request.BaseClaims{
-					UserID: refreshClaims.UserID,
-					UUID:   user.UUID,
-					RoleID: user.RoleID,
+					UserID:    refreshClaims.UserID,
+					UUID:      user.UUID,
+					RoleID:    user.RoleID,
+					SessionID: refreshClaims.SessionID,
 				})
 
 				newAccessToken, err := j.CreateAccessToken(newAccessClaims)
@@ -76,6 +102,14 @@ func JWTAuth() gin.HandlerFunc {
 			return
 		}
 
+		// 会话被注销后，访问令牌在过期前同样失效
+		if !sessionService.SessionActive(claims.SessionID) {
+			utils.ClearRefreshToken(c)
+			response.NoAuth("Session expired or revoked, please log in again", c)
+			c.Abort()
+			return
+		}
+
 		c.Set("claims", claims)
 		c.Next()
 	}
@@ -15,24 +15,27 @@ func LoginRecord() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		c.Next()
 
+		// gin 会在请求结束后复用 Context，需要在启动协程前读取请求信息
+		var userID uint
+		ip := c.ClientIP()
+		loginMethod := c.DefaultQuery("flag", "email") // 若未传递flag参数，则默认为"email"
+		userAgent := c.Request.UserAgent()
+		status := c.Writer.Status()
+		sessionID := c.GetString("session_id")
+
+		// 从请求上下文中获取用户ID，确保获取到的是当前请求的正确用户ID
+		if value, exists := c.Get("user_id"); exists {
+			if id, ok := value.(uint); ok {
+				userID = id
+			}
+		}
+
 		// 异步记录日志
 		go func() {
 			gaodeService := service.ServiceGroupApp.GaodeService
-			var userID uint
-			var address string
-			ip := c.ClientIP()
-			loginMethod := c.DefaultQuery("flag", "email") // 若未传递flag参数，则默认为"email"
-			userAgent := c.Request.UserAgent()
-
-			// 从请求上下文中获取用户ID，确保获取到的是当前请求的正确用户ID
-			if value, exists := c.Get("user_id"); exists {
-				if id, ok := value.(uint); ok {
-					userID = id
-				}
-			}
 
 			// 获取用户IP的地理位置
-			address = getAddressFromIP(ip, gaodeService)
+			address := getAddressFromIP(ip, gaodeService)
 
 			// 解析用户的浏览器、操作系统和设备信息
 			os, deviceInfo, browserInfo := parseUserAgent(userAgent)
@@ -46,13 +49,20 @@ func LoginRecord() gin.HandlerFunc {
 				OS:          os,
 				DeviceInfo:  deviceInfo,
 				BrowserInfo: browserInfo,
-				Status:      c.Writer.Status(),
+				Status:      status,
 			}
 
 			// 将登录记录存储到数据库
 			if err := global.DB.Create(&login).Error; err != nil {
 				global.Log.Error("Failed to record login", zap.Error(err))
 			}
+
+			// 登录成功时，将地址和设备信息同步到本次登录创建的会话
+			if sessionID != "" {
+				if err := service.ServiceGroupApp.SessionService.SessionUpdateDevice(sessionID, login); err != nil {
+					global.Log.Error("Failed to update session device", zap.Error(err))
+				}
+			}
 		}()
 	}
 }
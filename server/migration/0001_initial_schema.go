@@ -0,0 +1,205 @@
+package migration
+
+import "gorm.io/gorm"
+
+// initialSchema 创建初始的表结构，对于已通过 AutoMigrate 建表的数据库同样适用
+var initialSchema = Migration{
+	Version: 1,
+	Name:    "initial_schema",
+	Up: func(tx *gorm.DB) error {
+		return tx.Set("gorm:table_options", "ENGINE=InnoDB").AutoMigrate(initialSchemaModels()...)
+	},
+	Down: func(tx *gorm.DB) error {
+		return tx.Migrator().DropTable(initialSchemaModels()...)
+	},
+}
+
+func initialSchemaModels() []any {
+	return []any{
+		&advertisementV1{},
+		&articleCategoryV1{},
+		&articleLikeV1{},
+		&articleRevisionV1{},
+		&articleSlugV1{},
+		&articleTagV1{},
+		&commentV1{},
+		&feedbackV1{},
+		&footerLinkV1{},
+		&friendLinkV1{},
+		&imageV1{},
+		&jwtBlacklistV1{},
+		&loginV1{},
+		&seriesV1{},
+		&seriesArticleV1{},
+		&userV1{},
+	}
+}
+
+// 以下为初始表结构的模型快照
+
+type advertisementV1 struct {
+	BaseModel
+	AdImage string  `gorm:"size:255"`
+	Image   imageV1 `gorm:"foreignKey:AdImage;references:URL"`
+	Link    string
+	Title   string
+	Content string
+}
+
+func (advertisementV1) TableName() string { return "advertisements" }
+
+type articleCategoryV1 struct {
+	Category string `gorm:"primaryKey"`
+	Number   int
+}
+
+func (articleCategoryV1) TableName() string { return "article_categories" }
+
+type articleLikeV1 struct {
+	BaseModel
+	ArticleID string
+	UserID    uint
+	User      userV1 `gorm:"foreignKey:UserID"`
+}
+
+func (articleLikeV1) TableName() string { return "article_likes" }
+
+type articleRevisionV1 struct {
+	BaseModel
+	ArticleID string `gorm:"size:64;index"`
+	Title     string
+	Abstract  string   `gorm:"type:text"`
+	Content   string   `gorm:"type:longtext"`
+	Tags      []string `gorm:"serializer:json"`
+	Category  string
+	Cover     string
+	VersionAt string
+	EditorID  uint
+	Editor    userV1 `gorm:"foreignKey:EditorID"`
+}
+
+func (articleRevisionV1) TableName() string { return "article_revisions" }
+
+type articleSlugV1 struct {
+	BaseModel
+	Slug      string `gorm:"size:191;unique"`
+	ArticleID string `gorm:"size:64;index"`
+}
+
+func (articleSlugV1) TableName() string { return "article_slugs" }
+
+type articleTagV1 struct {
+	Tag    string `gorm:"primaryKey"`
+	Number int
+}
+
+func (articleTagV1) TableName() string { return "article_tags" }
+
+type commentV1 struct {
+	BaseModel
+	ArticleID string
+	PID       *uint
+	PComment  *commentV1  `gorm:"foreignKey:PID"`
+	Children  []commentV1 `gorm:"foreignKey:PID"`
+	UserUUID  string      `gorm:"type:char(36)"`
+	User      userV1      `gorm:"foreignKey:UserUUID;references:UUID"`
+	Content   string
+}
+
+func (commentV1) TableName() string { return "comments" }
+
+type feedbackV1 struct {
+	BaseModel
+	UserUUID string `gorm:"type:char(36)"`
+	User     userV1 `gorm:"foreignKey:UserUUID;references:UUID"`
+	Content  string
+	Reply    string
+}
+
+func (feedbackV1) TableName() string { return "feedbacks" }
+
+type footerLinkV1 struct {
+	Title string `gorm:"primaryKey"`
+	Link  string
+}
+
+func (footerLinkV1) TableName() string { return "footer_links" }
+
+type friendLinkV1 struct {
+	BaseModel
+	Logo        string  `gorm:"size:255"`
+	Image       imageV1 `gorm:"foreignKey:Logo;references:URL"`
+	Link        string
+	Name        string
+	Description string
+}
+
+func (friendLinkV1) TableName() string { return "friend_links" }
+
+type imageV1 struct {
+	BaseModel
+	Name     string
+	URL      string `gorm:"size:255;unique"`
+	Category int
+	Storage  int
+}
+
+func (imageV1) TableName() string { return "images" }
+
+type jwtBlacklistV1 struct {
+	BaseModel
+	Jwt string `gorm:"type:text"`
+}
+
+func (jwtBlacklistV1) TableName() string { return "jwt_blacklists" }
+
+type loginV1 struct {
+	BaseModel
+	UserID      uint
+	User        userV1 `gorm:"foreignKey:UserID"`
+	LoginMethod string
+	IP          string
+	Address     string
+	OS          string
+	DeviceInfo  string
+	BrowserInfo string
+	Status      int
+}
+
+func (loginV1) TableName() string { return "logins" }
+
+type seriesV1 struct {
+	BaseModel
+	Title       string
+	Description string            `gorm:"type:text"`
+	Cover       string            `gorm:"size:255"`
+	Articles    []seriesArticleV1 `gorm:"foreignKey:SeriesID"`
+}
+
+func (seriesV1) TableName() string { return "series" }
+
+type seriesArticleV1 struct {
+	BaseModel
+	SeriesID  uint   `gorm:"index"`
+	ArticleID string `gorm:"size:64;index"`
+	Sort      int
+}
+
+func (seriesArticleV1) TableName() string { return "series_articles" }
+
+type userV1 struct {
+	BaseModel
+	UUID      string `gorm:"type:char(36);unique"`
+	Username  string
+	Password  string
+	Email     string
+	Openid    string
+	Avatar    string `gorm:"size:255"`
+	Address   string
+	Signature string `gorm:"default:'签名是空白的，这位用户似乎比较低调。'"`
+	RoleID    int
+	Register  int
+	Freeze    bool
+}
+
+func (userV1) TableName() string { return "users" }
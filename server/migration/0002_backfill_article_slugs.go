@@ -0,0 +1,22 @@
+package migration
+
+import (
+	"server/service"
+
+	"gorm.io/gorm"
+)
+
+// backfillArticleSlugs 为引入 slug 之前创建的文章生成 slug
+// 全新安装时 ES 索引尚未创建，此时没有需要处理的文章，之后导入的文章由 --es-import 补全 slug
+var backfillArticleSlugs = Migration{
+	Version: 2,
+	Name:    "backfill_article_slugs",
+	Up: func(tx *gorm.DB) error {
+		_, err := service.ServiceGroupApp.ArticleService.ArticleBackfillSlugs()
+		return err
+	},
+	// 保留已生成的 slug，回滚时无需处理
+	Down: func(tx *gorm.DB) error {
+		return nil
+	},
+}
@@ -0,0 +1,31 @@
+package migration
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// createEsOutbox 创建文章计数同步的 outbox 表
+var createEsOutbox = Migration{
+	Version: 3,
+	Name:    "create_es_outbox",
+	Up: func(tx *gorm.DB) error {
+		return tx.Set("gorm:table_options", "ENGINE=InnoDB").AutoMigrate(&esOutboxV3{})
+	},
+	Down: func(tx *gorm.DB) error {
+		return tx.Migrator().DropTable(&esOutboxV3{})
+	},
+}
+
+type esOutboxV3 struct {
+	BaseModel
+	ArticleID   string `gorm:"size:64"`
+	Field       string `gorm:"size:32"`
+	Delta       int
+	Attempts    int
+	NextRetryAt time.Time `gorm:"index"`
+	LastError   string    `gorm:"type:text"`
+}
+
+func (esOutboxV3) TableName() string { return "es_outboxes" }
@@ -0,0 +1,39 @@
+package migration
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// createTwoFactor 创建两步验证和恢复码表
+var createTwoFactor = Migration{
+	Version: 4,
+	Name:    "create_two_factor",
+	Up: func(tx *gorm.DB) error {
+		return tx.Set("gorm:table_options", "ENGINE=InnoDB").AutoMigrate(&userTwoFactorV4{}, &userRecoveryCodeV4{})
+	},
+	Down: func(tx *gorm.DB) error {
+		return tx.Migrator().DropTable(&userTwoFactorV4{}, &userRecoveryCodeV4{})
+	},
+}
+
+type userTwoFactorV4 struct {
+	BaseModel
+	UserID       uint   `gorm:"unique"`
+	Secret       string `gorm:"size:64"`
+	Enabled      bool
+	EnabledAt    *time.Time
+	LastUsedStep uint64
+}
+
+func (userTwoFactorV4) TableName() string { return "user_two_factors" }
+
+type userRecoveryCodeV4 struct {
+	BaseModel
+	UserID   uint   `gorm:"index"`
+	CodeHash string `gorm:"size:64;index"`
+	Used     bool
+}
+
+func (userRecoveryCodeV4) TableName() string { return "user_recovery_codes" }
@@ -0,0 +1,36 @@
+package migration
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// createUserSessions 创建登录会话表
+var createUserSessions = Migration{
+	Version: 5,
+	Name:    "create_user_sessions",
+	Up: func(tx *gorm.DB) error {
+		return tx.Set("gorm:table_options", "ENGINE=InnoDB").AutoMigrate(&userSessionV5{})
+	},
+	Down: func(tx *gorm.DB) error {
+		return tx.Migrator().DropTable(&userSessionV5{})
+	},
+}
+
+type userSessionV5 struct {
+	BaseModel
+	SessionID        string `gorm:"type:char(36);unique"`
+	UserID           uint   `gorm:"index"`
+	RefreshTokenHash string `gorm:"size:64"`
+	IP               string
+	Address          string
+	OS               string
+	DeviceInfo       string
+	BrowserInfo      string
+	LastSeenAt       time.Time
+	ExpiresAt        time.Time `gorm:"index"`
+	RevokedAt        *time.Time
+}
+
+func (userSessionV5) TableName() string { return "user_sessions" }
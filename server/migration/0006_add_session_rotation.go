@@ -0,0 +1,41 @@
+package migration
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// addSessionRotation 为登录会话添加刷新令牌轮换所需的字段
+var addSessionRotation = Migration{
+	Version: 6,
+	Name:    "add_session_rotation",
+	Up: func(tx *gorm.DB) error {
+		for _, field := range []string{"PreviousHash", "RotatedAt"} {
+			if !tx.Migrator().HasColumn(&userSessionV6{}, field) {
+				if err := tx.Migrator().AddColumn(&userSessionV6{}, field); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	},
+	Down: func(tx *gorm.DB) error {
+		for _, field := range []string{"PreviousHash", "RotatedAt"} {
+			if tx.Migrator().HasColumn(&userSessionV6{}, field) {
+				if err := tx.Migrator().DropColumn(&userSessionV6{}, field); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	},
+}
+
+// userSessionV6 只包含本次新增的字段
+type userSessionV6 struct {
+	PreviousHash string `gorm:"size:64"`
+	RotatedAt    *time.Time
+}
+
+func (userSessionV6) TableName() string { return "user_sessions" }
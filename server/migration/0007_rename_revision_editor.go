@@ -0,0 +1,21 @@
+package migration
+
+import "gorm.io/gorm"
+
+// renameRevisionEditor 历史版本中记录的是用新版本替换该版本的用户，而不是该版本的作者，将 editor_id 重命名为 replaced_by_id
+var renameRevisionEditor = Migration{
+	Version: 7,
+	Name:    "rename_revision_editor",
+	Up: func(tx *gorm.DB) error {
+		if !tx.Migrator().HasColumn("article_revisions", "editor_id") {
+			return nil
+		}
+		return tx.Migrator().RenameColumn("article_revisions", "editor_id", "replaced_by_id")
+	},
+	Down: func(tx *gorm.DB) error {
+		if !tx.Migrator().HasColumn("article_revisions", "replaced_by_id") {
+			return nil
+		}
+		return tx.Migrator().RenameColumn("article_revisions", "replaced_by_id", "editor_id")
+	},
+}
@@ -0,0 +1,22 @@
+package migration
+
+import (
+	"server/service"
+
+	"gorm.io/gorm"
+)
+
+// backfillArticleSuggest 为引入搜索建议之前创建的文章生成候选词
+// 全新安装时 ES 索引尚未创建，此时没有需要处理的文章，之后导入的文章由 --es-import 补全候选词
+var backfillArticleSuggest = Migration{
+	Version: 8,
+	Name:    "backfill_article_suggest",
+	Up: func(tx *gorm.DB) error {
+		_, err := service.ServiceGroupApp.ArticleService.ArticleBackfillSuggest()
+		return err
+	},
+	// 保留已生成的候选词，回滚时无需处理
+	Down: func(tx *gorm.DB) error {
+		return nil
+	},
+}
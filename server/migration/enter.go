@@ -0,0 +1,38 @@
+package migration
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Migration 数据库迁移，按 Version 从小到大依次执行，既可以修改表结构，也可以迁移数据
+// Up 和 Down 在事务中执行，但 MySQL 的 DDL 会隐式提交，修改表结构的迁移失败后无法回滚，
+// 因此迁移必须可以重复执行，例如建表前检查表是否存在、加列前检查列是否存在
+type Migration struct {
+	Version uint                    // 版本号，必须唯一且只增不减
+	Name    string                  // 迁移名称
+	Up      func(tx *gorm.DB) error // 执行迁移
+	Down    func(tx *gorm.DB) error // 回滚迁移，为空表示该迁移无法回滚
+}
+
+// migrations 所有迁移，新增迁移时在此追加，已发布的迁移不能再修改
+// 迁移中使用的是各自定义的模型快照，而不是 database 包中的模型，模型以后的变化需要通过新的迁移完成
+var migrations = []Migration{
+	initialSchema,
+	backfillArticleSlugs,
+	createEsOutbox,
+	createTwoFactor,
+	createUserSessions,
+	addSessionRotation,
+	renameRevisionEditor,
+	backfillArticleSuggest,
+}
+
+// BaseModel 模型快照的公共字段，与 global.MODEL 的定义相同，需要导出才能被 GORM 作为嵌入字段解析
+type BaseModel struct {
+	ID        uint `gorm:"primarykey"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	DeletedAt gorm.DeletedAt `gorm:"index"`
+}
@@ -0,0 +1,154 @@
+package migration
+
+import (
+	"errors"
+	"fmt"
+	"server/global"
+	"server/model/database"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Status 迁移的执行状态
+type Status struct {
+	Version   uint
+	Name      string
+	Applied   bool
+	AppliedAt time.Time
+}
+
+// sortedMigrations 返回按版本号排序的迁移，并检查版本号是否重复
+func sortedMigrations() ([]Migration, error) {
+	sorted := make([]Migration, len(migrations))
+	copy(sorted, migrations)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Version < sorted[j].Version
+	})
+	for i := 1; i < len(sorted); i++ {
+		if sorted[i].Version == sorted[i-1].Version {
+			return nil, fmt.Errorf("duplicate migration version %d", sorted[i].Version)
+		}
+	}
+	return sorted, nil
+}
+
+// appliedMigrations 返回已执行的迁移记录
+func appliedMigrations() (map[uint]database.SchemaMigration, error) {
+	if err := global.DB.AutoMigrate(&database.SchemaMigration{}); err != nil {
+		return nil, err
+	}
+	var records []database.SchemaMigration
+	if err := global.DB.Find(&records).Error; err != nil {
+		return nil, err
+	}
+	applied := make(map[uint]database.SchemaMigration, len(records))
+	for _, record := range records {
+		applied[record.Version] = record
+	}
+	return applied, nil
+}
+
+// Up 依次执行所有未执行的迁移，返回本次执行的迁移
+func Up() ([]Migration, error) {
+	sorted, err := sortedMigrations()
+	if err != nil {
+		return nil, err
+	}
+	applied, err := appliedMigrations()
+	if err != nil {
+		return nil, err
+	}
+
+	var done []Migration
+	for _, migration := range sorted {
+		if _, ok := applied[migration.Version]; ok {
+			continue
+		}
+		err := global.DB.Transaction(func(tx *gorm.DB) error {
+			if err := migration.Up(tx); err != nil {
+				return err
+			}
+			return tx.Create(&database.SchemaMigration{
+				Version:   migration.Version,
+				Name:      migration.Name,
+				AppliedAt: time.Now(),
+			}).Error
+		})
+		if err != nil {
+			return done, fmt.Errorf("failed to apply migration %d_%s: %w", migration.Version, migration.Name, err)
+		}
+		done = append(done, migration)
+	}
+	return done, nil
+}
+
+// Last 返回最近一次执行的迁移，没有已执行的迁移时返回 false
+func Last() (Migration, bool, error) {
+	sorted, err := sortedMigrations()
+	if err != nil {
+		return Migration{}, false, err
+	}
+	applied, err := appliedMigrations()
+	if err != nil {
+		return Migration{}, false, err
+	}
+	for i := len(sorted) - 1; i >= 0; i-- {
+		if _, ok := applied[sorted[i].Version]; ok {
+			return sorted[i], true, nil
+		}
+	}
+	return Migration{}, false, nil
+}
+
+// Down 回滚最近一次执行的迁移，返回被回滚的迁移
+func Down() (Migration, error) {
+	migration, ok, err := Last()
+	if err != nil {
+		return Migration{}, err
+	}
+	if !ok {
+		return Migration{}, errors.New("no migration to roll back")
+	}
+	if migration.Down == nil {
+		return Migration{}, fmt.Errorf("migration %d_%s cannot be rolled back", migration.Version, migration.Name)
+	}
+
+	err = global.DB.Transaction(func(tx *gorm.DB) error {
+		if err := migration.Down(tx); err != nil {
+			return err
+		}
+		return tx.Delete(&database.SchemaMigration{}, migration.Version).Error
+	})
+	if err != nil {
+		return Migration{}, fmt.Errorf("failed to roll back migration %d_%s: %w", migration.Version, migration.Name, err)
+	}
+	return migration, nil
+}
+
+// StatusList 返回所有迁移的执行状态，包括数据库中存在但代码中已不存在的迁移
+func StatusList() ([]Status, error) {
+	sorted, err := sortedMigrations()
+	if err != nil {
+		return nil, err
+	}
+	applied, err := appliedMigrations()
+	if err != nil {
+		return nil, err
+	}
+
+	var list []Status
+	for _, migration := range sorted {
+		record, ok := applied[migration.Version]
+		list = append(list, Status{Version: migration.Version, Name: migration.Name, Applied: ok, AppliedAt: record.AppliedAt})
+		delete(applied, migration.Version)
+	}
+	for _, record := range applied {
+		list = append(list, Status{Version: record.Version, Name: record.Name, Applied: true, AppliedAt: record.AppliedAt})
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Version < list[j].Version
+	})
+	return list, nil
+}
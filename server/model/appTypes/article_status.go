@@ -0,0 +1,62 @@
+package appTypes
+
+import "encoding/json"
+
+// ArticleStatus 文章状态
+type ArticleStatus int
+
+const (
+	Published ArticleStatus = iota // 已发布
+	Draft                          // 草稿
+	Scheduled                      // 定时发布
+	Archived                       // 已归档
+)
+
+// MarshalJSON 实现了 json.Marshaler 接口
+func (s ArticleStatus) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.String())
+}
+
+// UnmarshalJSON 实现了 json.Unmarshaler 接口
+func (s *ArticleStatus) UnmarshalJSON(data []byte) error {
+	var str string
+	if err := json.Unmarshal(data, &str); err != nil {
+		return err
+	}
+	*s = ToArticleStatus(str)
+	return nil
+}
+
+// String 方法返回 ArticleStatus 的字符串表示
+func (s ArticleStatus) String() string {
+	var str string
+	switch s {
+	case Published:
+		str = "已发布"
+	case Draft:
+		str = "草稿"
+	case Scheduled:
+		str = "定时发布"
+	case Archived:
+		str = "已归档"
+	default:
+		str = "未知状态"
+	}
+	return str
+}
+
+// ToArticleStatus 函数将字符串转换为 ArticleStatus
+func ToArticleStatus(str string) ArticleStatus {
+	switch str {
+	case "已发布":
+		return Published
+	case "草稿":
+		return Draft
+	case "定时发布":
+		return Scheduled
+	case "已归档":
+		return Archived
+	default:
+		return -1
+	}
+}
@@ -0,0 +1,27 @@
+package appTypes
+
+// EmailPurpose 邮箱验证码的用途，不同用途的验证码相互独立
+type EmailPurpose string
+
+const (
+	EmailRegister       EmailPurpose = "register"        // 注册
+	EmailForgotPassword EmailPurpose = "forgot_password" // 找回密码
+	EmailChangeEmail    EmailPurpose = "change_email"    // 更换邮箱
+	EmailDeleteAccount  EmailPurpose = "delete_account"  // 注销账号
+)
+
+// String 方法返回 EmailPurpose 的中文描述
+func (p EmailPurpose) String() string {
+	switch p {
+	case EmailRegister:
+		return "注册"
+	case EmailForgotPassword:
+		return "找回密码"
+	case EmailChangeEmail:
+		return "更换邮箱"
+	case EmailDeleteAccount:
+		return "注销账号"
+	default:
+		return "未知"
+	}
+}
@@ -0,0 +1,18 @@
+package database
+
+import "server/global"
+
+// ArticleRevision 文章历史版本表
+type ArticleRevision struct {
+	global.MODEL
+	ArticleID    string   `json:"article_id" gorm:"size:64;index"` // 文章 ID
+	Title        string   `json:"title"`                           // 文章标题
+	Abstract     string   `json:"abstract" gorm:"type:text"`       // 文章简介
+	Content      string   `json:"content" gorm:"type:longtext"`    // 文章内容
+	Tags         []string `json:"tags" gorm:"serializer:json"`     // 文章标签
+	Category     string   `json:"category"`                        // 文章类别
+	Cover        string   `json:"cover"`                           // 文章封面
+	VersionAt    string   `json:"version_at"`                      // 该版本的更新时间
+	ReplacedByID uint     `json:"replaced_by_id"`                  // 用新版本替换该版本的用户 ID，不是该版本的作者
+	ReplacedBy   User     `json:"replaced_by" gorm:"foreignKey:ReplacedByID"`
+}
@@ -0,0 +1,10 @@
+package database
+
+import "server/global"
+
+// ArticleSlug 文章历史 slug 表，修改标题后旧的 slug 会被重定向到文章当前的 slug
+type ArticleSlug struct {
+	global.MODEL
+	Slug      string `json:"slug" gorm:"size:191;unique"`     // 历史 slug
+	ArticleID string `json:"article_id" gorm:"size:64;index"` // 文章 ID
+}
@@ -0,0 +1,28 @@
+package database
+
+// Models 所有需要备份和恢复的数据表模型，表结构的变更通过 migration 包中的迁移完成
+func Models() []any {
+	return []any{
+		&Advertisement{},
+		&ArticleCategory{},
+		&ArticleLike{},
+		&ArticleRevision{},
+		&ArticleSlug{},
+		&ArticleTag{},
+		&Comment{},
+		&EsOutbox{},
+		&Feedback{},
+		&FooterLink{},
+		&FriendLink{},
+		&Image{},
+		&JwtBlacklist{},
+		&Login{},
+		&SchemaMigration{},
+		&Series{},
+		&SeriesArticle{},
+		&User{},
+		&UserRecoveryCode{},
+		&UserSession{},
+		&UserTwoFactor{},
+	}
+}
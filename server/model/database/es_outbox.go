@@ -0,0 +1,17 @@
+package database
+
+import (
+	"server/global"
+	"time"
+)
+
+// EsOutbox 待同步到 Elasticsearch 的文章计数变更，与业务数据在同一事务中写入
+type EsOutbox struct {
+	global.MODEL
+	ArticleID   string    `json:"article_id" gorm:"size:64"`   // 文章 ID
+	Field       string    `json:"field" gorm:"size:32"`        // 计数字段，例如 comments、likes
+	Delta       int       `json:"delta"`                       // 变化量
+	Attempts    int       `json:"attempts"`                    // 已尝试同步的次数
+	NextRetryAt time.Time `json:"next_retry_at" gorm:"index"`  // 下次同步的时间
+	LastError   string    `json:"last_error" gorm:"type:text"` // 最近一次同步失败的原因
+}
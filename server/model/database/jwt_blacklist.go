@@ -2,7 +2,7 @@ package database
 
 import "server/global"
 
-// JwtBlacklist JWT 黑名单表
+// JwtBlacklist JWT 黑名单表，令牌的注销已改为通过登录会话完成，该表不再使用，保留以兼容旧的数据和备份
 type JwtBlacklist struct {
 	global.MODEL
 	Jwt string `json:"jwt" gorm:"type:text"` // Jwt
@@ -0,0 +1,10 @@
+package database
+
+import "time"
+
+// SchemaMigration 已执行的数据库迁移记录表
+type SchemaMigration struct {
+	Version   uint      `json:"version" gorm:"primarykey;autoIncrement:false"` // 迁移版本号
+	Name      string    `json:"name" gorm:"size:255"`                          // 迁移名称
+	AppliedAt time.Time `json:"applied_at"`                                    // 执行时间
+}
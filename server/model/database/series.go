@@ -0,0 +1,20 @@
+package database
+
+import "server/global"
+
+// Series 文章系列表，用于将多篇文章组织为一个有序的合集
+type Series struct {
+	global.MODEL
+	Title       string          `json:"title"`                        // 标题
+	Description string          `json:"description" gorm:"type:text"` // 简介
+	Cover       string          `json:"cover" gorm:"size:255"`        // 封面
+	Articles    []SeriesArticle `json:"articles" gorm:"foreignKey:SeriesID"`
+}
+
+// SeriesArticle 文章系列与文章的关联表
+type SeriesArticle struct {
+	global.MODEL
+	SeriesID  uint   `json:"series_id" gorm:"index"`          // 系列 ID
+	ArticleID string `json:"article_id" gorm:"size:64;index"` // 文章 ID
+	Sort      int    `json:"sort"`                            // 在系列中的顺序，从 1 开始
+}
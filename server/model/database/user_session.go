@@ -0,0 +1,24 @@
+package database
+
+import (
+	"server/global"
+	"time"
+)
+
+// UserSession 登录会话表，每次登录创建一个会话，每个会话持有自己的刷新令牌
+type UserSession struct {
+	global.MODEL
+	SessionID        string     `json:"session_id" gorm:"type:char(36);unique"` // 会话 ID，写入令牌的 Claims 中
+	UserID           uint       `json:"user_id" gorm:"index"`                   // 用户 ID
+	RefreshTokenHash string     `json:"-" gorm:"size:64"`                       // 当前刷新令牌的 SHA-256 哈希值
+	PreviousHash     string     `json:"-" gorm:"size:64"`                       // 上一个刷新令牌的 SHA-256 哈希值，用于识别并发刷新
+	RotatedAt        *time.Time `json:"-"`                                      // 最近一次轮换刷新令牌的时间
+	IP               string     `json:"ip"`                                     // 登录 IP 地址
+	Address          string     `json:"address"`                                // 登录地址
+	OS               string     `json:"os"`                                     // 操作系统
+	DeviceInfo       string     `json:"device_info"`                            // 设备信息
+	BrowserInfo      string     `json:"browser_info"`                           // 浏览器信息
+	LastSeenAt       time.Time  `json:"last_seen_at"`                           // 最近活跃时间
+	ExpiresAt        time.Time  `json:"expires_at" gorm:"index"`                // 刷新令牌的过期时间
+	RevokedAt        *time.Time `json:"revoked_at"`                             // 注销时间，为空表示会话有效
+}
@@ -0,0 +1,24 @@
+package database
+
+import (
+	"server/global"
+	"time"
+)
+
+// UserTwoFactor 用户两步验证表
+type UserTwoFactor struct {
+	global.MODEL
+	UserID       uint       `json:"user_id" gorm:"unique"` // 用户 ID
+	Secret       string     `json:"-" gorm:"size:64"`      // TOTP 密钥
+	Enabled      bool       `json:"enabled"`               // 是否已确认启用
+	EnabledAt    *time.Time `json:"enabled_at"`            // 启用时间
+	LastUsedStep uint64     `json:"-"`                     // 最近一次使用的验证码时间步，防止验证码被重复使用
+}
+
+// UserRecoveryCode 两步验证恢复码表
+type UserRecoveryCode struct {
+	global.MODEL
+	UserID   uint   `json:"user_id" gorm:"index"`   // 用户 ID
+	CodeHash string `json:"-" gorm:"size:64;index"` // 恢复码的 SHA-256 哈希值
+	Used     bool   `json:"used"`                   // 是否已使用
+}
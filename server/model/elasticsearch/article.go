@@ -1,6 +1,9 @@
 package elasticsearch
 
 import (
+	"server/model/appTypes"
+	"server/model/other"
+
 	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
 )
 
@@ -9,17 +12,31 @@ type Article struct {
 	CreatedAt string `json:"created_at"` // 创建时间
 	UpdatedAt string `json:"updated_at"` // 更新时间
 
-	Cover    string   `json:"cover"`    // 文章封面
-	Title    string   `json:"title"`    // 文章标题
-	Keyword  string   `json:"keyword"`  // 文章标题-关键字
-	Category string   `json:"category"` // 文章类别
-	Tags     []string `json:"tags"`     // 文章标签
-	Abstract string   `json:"abstract"` // 文章简介
-	Content  string   `json:"content"`  // 文章内容
+	Cover    string   `json:"cover"`             // 文章封面
+	Title    string   `json:"title"`             // 文章标题
+	Keyword  string   `json:"keyword"`           // 文章标题-关键字
+	Slug     string   `json:"slug"`              // 文章链接别名，由标题生成
+	Category string   `json:"category"`          // 文章类别
+	Tags     []string `json:"tags"`              // 文章标签
+	Abstract string   `json:"abstract"`          // 文章简介
+	Content  string   `json:"content"`           // 文章内容
+	Suggest  []string `json:"suggest,omitempty"` // 搜索建议的候选词，由标题和标签组成
+
+	ContentHTML string          `json:"content_html"` // 渲染后的文章内容
+	Toc         []other.TocItem `json:"toc"`          // 文章目录
+	WordCount   int             `json:"word_count"`   // 字数
+	ReadingTime int             `json:"reading_time"` // 预计阅读时间，单位为分钟
 
 	Views    int `json:"views"`    // 浏览量
 	Comments int `json:"comments"` // 评论量
 	Likes    int `json:"likes"`    // 收藏量
+
+	Pinned   bool `json:"pinned"`    // 是否置顶
+	PinOrder int  `json:"pin_order"` // 置顶顺序，数值越小越靠前
+	Featured bool `json:"featured"`  // 是否精选
+
+	Status    appTypes.ArticleStatus `json:"status"`               // 文章状态
+	PublishAt string                 `json:"publish_at,omitempty"` // 定时发布时间
 }
 
 // ArticleIndex 文章 ES 索引
@@ -31,20 +48,33 @@ func ArticleIndex() string {
 func ArticleMapping() *types.TypeMapping {
 	return &types.TypeMapping{
 		Properties: map[string]types.Property{
-			"created_at": types.DateProperty{NullValue: nil, Format: func(s string) *string { return &s }("yyyy-MM-dd HH:mm:ss")},
-			"updated_at": types.DateProperty{NullValue: nil, Format: func(s string) *string { return &s }("yyyy-MM-dd HH:mm:ss")},
-			"cover":      types.TextProperty{},
-			"title":      types.TextProperty{Analyzer: strPtr("ik_smart")},
-			"keyword":    types.KeywordProperty{},
-			"category":   types.KeywordProperty{},
-			"tags":       []types.KeywordProperty{},
-			"abstract":   types.TextProperty{Analyzer: strPtr("ik_smart")},
-			"content":    types.TextProperty{Analyzer: strPtr("ik_smart")},
-			"views":      types.IntegerNumberProperty{},
-			"comments":   types.IntegerNumberProperty{},
-			"likes":      types.IntegerNumberProperty{},
+			"created_at":   types.DateProperty{NullValue: nil, Format: func(s string) *string { return &s }("yyyy-MM-dd HH:mm:ss")},
+			"updated_at":   types.DateProperty{NullValue: nil, Format: func(s string) *string { return &s }("yyyy-MM-dd HH:mm:ss")},
+			"cover":        types.TextProperty{},
+			"title":        types.TextProperty{Analyzer: strPtr("ik_smart")},
+			"keyword":      types.KeywordProperty{},
+			"slug":         types.KeywordProperty{},
+			"category":     types.KeywordProperty{},
+			"tags":         []types.KeywordProperty{},
+			"abstract":     types.TextProperty{Analyzer: strPtr("ik_smart")},
+			"content":      types.TextProperty{Analyzer: strPtr("ik_smart")},
+			"suggest":      types.CompletionProperty{Contexts: []types.SuggestContext{{Name: "status", Type: "category", Path: strPtr("status")}}},
+			"content_html": types.TextProperty{Index: boolPtr(false)},
+			"toc":          types.ObjectProperty{Enabled: boolPtr(false)},
+			"word_count":   types.IntegerNumberProperty{},
+			"reading_time": types.IntegerNumberProperty{},
+			"views":        types.IntegerNumberProperty{},
+			"comments":     types.IntegerNumberProperty{},
+			"likes":        types.IntegerNumberProperty{},
+			"pinned":       types.BooleanProperty{},
+			"pin_order":    types.IntegerNumberProperty{},
+			"featured":     types.BooleanProperty{},
+			"status":       types.KeywordProperty{},
+			"publish_at":   types.DateProperty{NullValue: nil, Format: func(s string) *string { return &s }("yyyy-MM-dd HH:mm:ss")},
 		},
 	}
 }
 
 func strPtr(s string) *string { return &s }
+
+func boolPtr(b bool) *bool { return &b }
@@ -0,0 +1,26 @@
+package other
+
+import "time"
+
+// BackupManifest 备份包清单
+type BackupManifest struct {
+	Version   int          `json:"version"`    // 备份格式版本
+	CreatedAt string       `json:"created_at"` // 备份时间
+	Tables    []string     `json:"tables"`     // 备份的数据表
+	Documents int          `json:"documents"`  // 备份的 ES 文档数量
+	Files     []BackupFile `json:"files"`      // 备份包中的文件及其校验值
+}
+
+// BackupFile 备份包中的文件
+type BackupFile struct {
+	Name   string `json:"name"`   // 文件在备份包中的路径
+	Size   int64  `json:"size"`   // 文件大小
+	SHA256 string `json:"sha256"` // 文件的 SHA-256 校验值
+}
+
+// BackupObject 备份存储中的文件
+type BackupObject struct {
+	Key     string    // 文件在备份存储中的名称，格式为 "<备份名称>.tar.gz"
+	Size    int64     // 文件大小
+	ModTime time.Time // 文件的修改时间
+}
@@ -0,0 +1,9 @@
+package other
+
+// DiffLine 文本按行比较的结果
+type DiffLine struct {
+	Type    string `json:"type"`     // 类型：equal 未修改，insert 新增，delete 删除
+	OldLine int    `json:"old_line"` // 在旧文本中的行号，新增的行为 0
+	NewLine int    `json:"new_line"` // 在新文本中的行号，删除的行为 0
+	Content string `json:"content"`  // 行内容
+}
@@ -11,3 +11,9 @@ type Data struct {
 type ESIndexResponse struct {
 	Data []Data `json:"data"`
 }
+
+// EsPosition ES 文档遍历位置，用于分批遍历时从中断处继续
+type EsPosition struct {
+	CreatedAt string   `json:"created_at"` // 最后处理文档的创建时间
+	IDs       []string `json:"ids"`        // 创建时间等于 CreatedAt 的已处理文档 ID
+}
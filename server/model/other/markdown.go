@@ -0,0 +1,28 @@
+package other
+
+// ArticleFrontMatter Markdown 文章文件开头的 YAML front matter
+type ArticleFrontMatter struct {
+	Title     string   `yaml:"title"`                // 标题
+	Category  string   `yaml:"category"`             // 类别
+	Tags      []string `yaml:"tags"`                 // 标签
+	Cover     string   `yaml:"cover,omitempty"`      // 封面
+	Abstract  string   `yaml:"abstract,omitempty"`   // 简介
+	Date      string   `yaml:"date,omitempty"`       // 创建时间
+	Status    string   `yaml:"status,omitempty"`     // 文章状态，可以是 published、draft、scheduled 或 archived，未设置时视为草稿
+	PublishAt string   `yaml:"publish_at,omitempty"` // 定时发布时间
+}
+
+// TocItem 文章目录中的一个标题
+type TocItem struct {
+	Level int    `json:"level"` // 标题级别，1 到 6
+	Text  string `json:"text"`  // 标题文本
+	ID    string `json:"id"`    // 标题的锚点 ID
+}
+
+// MarkdownRender Markdown 渲染结果
+type MarkdownRender struct {
+	HTML        string    // 经过安全过滤的 HTML
+	Toc         []TocItem // 目录
+	WordCount   int       // 字数，中日韩文字按字计算，其他文字按单词计算
+	ReadingTime int       // 预计阅读时间，单位为分钟
+}
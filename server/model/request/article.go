@@ -1,18 +1,36 @@
 package request
 
+import "server/model/appTypes"
+
 type ArticleInfoByID struct {
 	ID string `json:"id" form:"id" uri:"id" binding:"required"`
 }
 
+type ArticleInfoBySlug struct {
+	Slug string `json:"slug" form:"slug" uri:"slug" binding:"required"`
+}
+
 type ArticleSearch struct {
-	Query    string `json:"query" form:"query" uri:"query" binding:"max=50"`
-	Category string `json:"category" form:"category" uri:"category"`
-	Tag      string `json:"tag" form:"tag" uri:"tag"`
-	Sort     string `json:"sort" form:"sort" uri:"sort"`
-	Order    string `json:"order" form:"order" uri:"order" binding:"required"`
+	Query      string   `json:"query" form:"query" uri:"query" binding:"max=50"`
+	Category   string   `json:"category" form:"category" uri:"category"`
+	Categories []string `json:"categories" form:"categories"` // 匹配任意一个类别
+	Tag        string   `json:"tag" form:"tag" uri:"tag"`
+	Tags       []string `json:"tags" form:"tags"`
+	TagMode    string   `json:"tag_mode" form:"tag_mode" binding:"omitempty,oneof=and or"` // and 需要包含所有标签，or 包含任意一个标签即可，默认为 or
+	StartDate  string   `json:"start_date" form:"start_date" binding:"omitempty,datetime=2006-01-02"`
+	EndDate    string   `json:"end_date" form:"end_date" binding:"omitempty,datetime=2006-01-02"`
+	MinViews   int      `json:"min_views" form:"min_views" binding:"min=0"`
+	MinLikes   int      `json:"min_likes" form:"min_likes" binding:"min=0"`
+	Sort       string   `json:"sort" form:"sort" uri:"sort"`
+	Order      string   `json:"order" form:"order" uri:"order" binding:"required"`
 	PageInfo
 }
 
+type ArticleSuggest struct {
+	Prefix string `json:"prefix" form:"prefix" binding:"required,max=50"`
+	Size   int    `json:"size" form:"size" binding:"max=20"`
+}
+
 type ArticleLike struct {
 	UserID    uint   `json:"-"`
 	ArticleID string `json:"article_id" form:"article_id" binding:"required"`
@@ -24,12 +42,15 @@ type ArticleLikesList struct {
 }
 
 type ArticleCreate struct {
-	Cover    string   `json:"cover" binding:"required"`
-	Title    string   `json:"title" binding:"required"`
-	Category string   `json:"category" binding:"required"`
-	Tags     []string `json:"tags" binding:"required"`
-	Abstract string   `json:"abstract" binding:"required"`
-	Content  string   `json:"content" binding:"required"`
+	Cover     string                 `json:"cover" binding:"required"`
+	Title     string                 `json:"title" binding:"required"`
+	Category  string                 `json:"category" binding:"required"`
+	Tags      []string               `json:"tags" binding:"required"`
+	Abstract  string                 `json:"abstract" binding:"required"`
+	Content   string                 `json:"content" binding:"required"`
+	Status    appTypes.ArticleStatus `json:"status"`
+	PublishAt string                 `json:"publish_at"`
+	CreatedAt string                 `json:"-"`
 }
 
 type ArticleDelete struct {
@@ -37,6 +58,7 @@ type ArticleDelete struct {
 }
 
 type ArticleUpdate struct {
+	EditorID uint     `json:"-"`
 	ID       string   `json:"id" binding:"required"`
 	Cover    string   `json:"cover" binding:"required"`
 	Title    string   `json:"title" binding:"required"`
@@ -46,9 +68,74 @@ type ArticleUpdate struct {
 	Content  string   `json:"content" binding:"required"`
 }
 
+type ArticleStatusUpdate struct {
+	ID        string                 `json:"id" binding:"required"`
+	Status    appTypes.ArticleStatus `json:"status"`
+	PublishAt string                 `json:"publish_at"`
+}
+
+type ArticlePin struct {
+	ID       string `json:"id" binding:"required"`
+	Pinned   bool   `json:"pinned"`
+	PinOrder int    `json:"pin_order"`
+}
+
+type ArticleFeature struct {
+	ID       string `json:"id" binding:"required"`
+	Featured bool   `json:"featured"`
+}
+
+type ArticleFeatured struct {
+	Size int `json:"size" form:"size" binding:"max=20"`
+}
+
 type ArticleList struct {
 	Title    *string `json:"title" form:"title"`
 	Category *string `json:"category" form:"category"`
 	Abstract *string `json:"abstract" form:"abstract"`
+	Status   *string `json:"status" form:"status"`
+	PageInfo
+}
+
+type ArticleRevisionList struct {
+	ArticleID string `json:"article_id" form:"article_id" binding:"required"`
+	PageInfo
+}
+
+type ArticleRevisionDiff struct {
+	From uint `json:"from" form:"from" binding:"required"`
+	To   uint `json:"to" form:"to"` // 为 0 时与文章当前版本比较
+}
+
+type ArticleRevisionRestore struct {
+	EditorID uint `json:"-"`
+	ID       uint `json:"id" binding:"required"`
+}
+
+type SeriesCreate struct {
+	Title       string   `json:"title" binding:"required"`
+	Description string   `json:"description"`
+	Cover       string   `json:"cover"`
+	ArticleIDs  []string `json:"article_ids"`
+}
+
+type SeriesDelete struct {
+	IDs []uint `json:"ids" binding:"required"`
+}
+
+type SeriesUpdate struct {
+	ID          uint     `json:"id" binding:"required"`
+	Title       string   `json:"title" binding:"required"`
+	Description string   `json:"description"`
+	Cover       string   `json:"cover"`
+	ArticleIDs  []string `json:"article_ids"`
+}
+
+type SeriesInfo struct {
+	ID uint `json:"id" uri:"id" binding:"required"`
+}
+
+type SeriesList struct {
+	Title *string `json:"title" form:"title"`
 	PageInfo
 }
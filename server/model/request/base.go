@@ -1,7 +1,10 @@
 package request
 
+import "server/model/appTypes"
+
 type SendEmailVerificationCode struct {
-	Email     string `json:"email" binding:"required,email"`
-	Captcha   string `json:"captcha" binding:"required,len=6"`
-	CaptchaID string `json:"captcha_id" binding:"required"`
+	Email     string                `json:"email" binding:"required,email"`
+	Purpose   appTypes.EmailPurpose `json:"purpose" binding:"omitempty,oneof=register forgot_password change_email delete_account"`
+	Captcha   string                `json:"captcha" binding:"required,len=6"`
+	CaptchaID string                `json:"captcha_id" binding:"required"`
 }
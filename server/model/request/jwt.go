@@ -15,13 +15,15 @@ type JwtCustomClaims struct {
 
 // JwtCustomRefreshClaims 结构体用于存储刷新Token的自定义Claims，包含用户ID和标准的JWT注册信息
 type JwtCustomRefreshClaims struct {
-	UserID               uint // 用户ID，用于与刷新Token相关的身份验证
-	jwt.RegisteredClaims      // 标准JWT声明
+	UserID               uint   // 用户ID，用于与刷新Token相关的身份验证
+	SessionID            string // 会话ID，刷新Token只在所属会话有效时可用
+	jwt.RegisteredClaims        // 标准JWT声明
 }
 
 // BaseClaims 结构体用于存储基本的用户信息，作为JWT的Claim部分
 type BaseClaims struct {
-	UserID uint            // 用户ID，标识用户唯一性
-	UUID   uuid.UUID       // 用户的UUID，唯一标识用户
-	RoleID appTypes.RoleID // 用户角色ID，表示用户的权限级别
+	UserID    uint            // 用户ID，标识用户唯一性
+	UUID      uuid.UUID       // 用户的UUID，唯一标识用户
+	RoleID    appTypes.RoleID // 用户角色ID，表示用户的权限级别
+	SessionID string          // 会话ID，标识本次登录
 }
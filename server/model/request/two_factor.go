@@ -0,0 +1,12 @@
+package request
+
+type TwoFactorCode struct {
+	UserID uint   `json:"-"`
+	Code   string `json:"code" binding:"required,max=32"` // 身份验证器中的 6 位验证码，或一个恢复码
+}
+
+type TwoFactorLogin struct {
+	IP    string `json:"-"`
+	Token string `json:"two_factor_token" binding:"required"`
+	Code  string `json:"code" binding:"required,max=32"` // 身份验证器中的 6 位验证码，或一个恢复码
+}
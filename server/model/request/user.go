@@ -37,6 +37,19 @@ type UserChangeInfo struct {
 	Signature string `json:"signature" binding:"max=320"`
 }
 
+type UserChangeEmail struct {
+	UserID           uint   `json:"-"`
+	Email            string `json:"email" binding:"required,email"`
+	VerificationCode string `json:"verification_code" binding:"required,len=6"`
+}
+
+type UserDeleteAccount struct {
+	UserID           uint   `json:"-"`
+	SessionID        string `json:"-"`
+	Password         string `json:"password" binding:"max=16"`         // 设置了密码的用户需要输入密码确认
+	VerificationCode string `json:"verification_code" binding:"max=6"` // 没有密码但绑定了邮箱的用户需要输入用途为 delete_account 的邮箱验证码
+}
+
 type UserChart struct {
 	Date int `json:"date" form:"date" binding:"required,oneof=7 30 90 180 365"`
 }
@@ -55,3 +68,7 @@ type UserLoginList struct {
 	UUID *string `json:"uuid" form:"uuid"`
 	PageInfo
 }
+
+type UserSessionRevoke struct {
+	SessionID string `json:"session_id" binding:"required"`
+}
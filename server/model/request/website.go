@@ -3,3 +3,8 @@ package request
 type WebsiteCarouselOperation struct {
 	Url string `json:"url" binding:"required"`
 }
+
+type WebsiteFeed struct {
+	Category string `json:"category" form:"category"`
+	Tag      string `json:"tag" form:"tag"`
+}
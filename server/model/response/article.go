@@ -0,0 +1,81 @@
+package response
+
+import (
+	"server/model/database"
+	"server/model/elasticsearch"
+	"server/model/other"
+	"time"
+)
+
+type ArticleHit struct {
+	Id_     string                `json:"_id"`
+	Source_ elasticsearch.Article `json:"_source"`
+	Series  []ArticleSeriesNav    `json:"series,omitempty"`
+}
+
+type ArticleRevisionDiff struct {
+	From database.ArticleRevision `json:"from"`
+	To   database.ArticleRevision `json:"to"`
+	Diff []other.DiffLine         `json:"diff"`
+}
+
+type ArticleInfo struct {
+	elasticsearch.Article
+	Series []ArticleSeriesNav `json:"series,omitempty"`
+}
+
+// ArticleSeriesNav 文章在系列中的位置，以及系列中的上一篇和下一篇文章
+type ArticleSeriesNav struct {
+	SeriesID uint               `json:"series_id"`
+	Title    string             `json:"title"`
+	Position int                `json:"position"`
+	Total    int                `json:"total"`
+	Prev     *SeriesArticleLink `json:"prev"`
+	Next     *SeriesArticleLink `json:"next"`
+}
+
+type SeriesArticleLink struct {
+	ID    string `json:"id"`
+	Title string `json:"title"`
+	Slug  string `json:"slug"`
+}
+
+type SeriesInfo struct {
+	ID          uint         `json:"id"`
+	CreatedAt   time.Time    `json:"created_at"`
+	UpdatedAt   time.Time    `json:"updated_at"`
+	Title       string       `json:"title"`
+	Description string       `json:"description"`
+	Cover       string       `json:"cover"`
+	ArticleList []ArticleHit `json:"article_list"`
+}
+
+type ArticleImport struct {
+	Total  int      `json:"total"`
+	Errors []string `json:"errors"`
+}
+
+type ArticleSuggest struct {
+	Text  string `json:"text"`
+	ID    string `json:"id"`
+	Title string `json:"title"`
+	Slug  string `json:"slug"`
+}
+
+type ArticleSearch struct {
+	List   interface{}   `json:"list"`
+	Total  int64         `json:"total"`
+	Facets ArticleFacets `json:"facets"`
+}
+
+// ArticleFacets 搜索结果按类别、标签和月份统计的文章数量
+type ArticleFacets struct {
+	Categories []FacetCount `json:"categories"`
+	Tags       []FacetCount `json:"tags"`
+	Months     []FacetCount `json:"months"`
+}
+
+type FacetCount struct {
+	Key   string `json:"key"`
+	Count int64  `json:"count"`
+}
@@ -0,0 +1,8 @@
+package response
+
+// BackupInfo 自动备份信息
+type BackupInfo struct {
+	Name      string `json:"name"`
+	CreatedAt string `json:"created_at"`
+	Size      int64  `json:"size"`
+}
@@ -0,0 +1,22 @@
+package response
+
+type TwoFactorEnroll struct {
+	Secret string `json:"secret"`
+	URL    string `json:"url"` // otpauth:// 链接，可生成二维码供身份验证器扫描
+}
+
+type TwoFactorRecoveryCodes struct {
+	RecoveryCodes []string `json:"recovery_codes"` // 恢复码只在生成时返回一次
+}
+
+type TwoFactorStatus struct {
+	Enabled       bool  `json:"enabled"`
+	Required      bool  `json:"required"`       // 当前用户是否被要求开启两步验证
+	RecoveryCodes int64 `json:"recovery_codes"` // 剩余可用的恢复码数量
+}
+
+type TwoFactorChallenge struct {
+	TwoFactorRequired bool   `json:"two_factor_required"`
+	TwoFactorToken    string `json:"two_factor_token"`
+	ExpiresAt         int64  `json:"expires_at"`
+}
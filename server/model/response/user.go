@@ -25,3 +25,8 @@ type UserChart struct {
 	LoginData    []int    `json:"login_data"`
 	RegisterData []int    `json:"register_data"`
 }
+
+type UserSession struct {
+	database.UserSession
+	Current bool `json:"current"` // 是否为发起请求的会话
+}
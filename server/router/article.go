@@ -22,14 +22,27 @@ func (a *ArticleRouter) InitArticleRouter(Router *gin.RouterGroup, PublicRouter
 	}
 	{
 		articlePublicRouter.GET("search", articleApi.ArticleSearch)
+		articlePublicRouter.GET("search/suggest", articleApi.ArticleSuggest)
 		articlePublicRouter.GET("category", articleApi.ArticleCategory)
 		articlePublicRouter.GET("tags", articleApi.ArticleTags)
+		articlePublicRouter.GET("featured", articleApi.ArticleFeatured)
 		articlePublicRouter.GET(":id", articleApi.ArticleInfoByID)
+		articlePublicRouter.GET(":id/related", articleApi.ArticleRelated)
+		articlePublicRouter.GET("slug/:slug", articleApi.ArticleInfoBySlug)
 	}
 	{
 		articleAdminRouter.POST("create", articleApi.ArticleCreate)
 		articleAdminRouter.DELETE("delete", articleApi.ArticleDelete)
 		articleAdminRouter.PUT("update", articleApi.ArticleUpdate)
 		articleAdminRouter.GET("list", articleApi.ArticleList)
+		articleAdminRouter.PUT("status", articleApi.ArticleStatusUpdate)
+		articleAdminRouter.PUT("pin", articleApi.ArticlePin)
+		articleAdminRouter.PUT("feature", articleApi.ArticleFeature)
+		articleAdminRouter.GET("detail/:id", articleApi.ArticleDetail)
+		articleAdminRouter.GET("revisions", articleApi.ArticleRevisionList)
+		articleAdminRouter.GET("revisionDiff", articleApi.ArticleRevisionDiff)
+		articleAdminRouter.POST("revisionRestore", articleApi.ArticleRevisionRestore)
+		articleAdminRouter.POST("import", articleApi.ArticleImport)
+		articleAdminRouter.GET("export", articleApi.ArticleExport)
 	}
 }
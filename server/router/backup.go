@@ -0,0 +1,19 @@
+package router
+
+import (
+	"server/api"
+
+	"github.com/gin-gonic/gin"
+)
+
+type BackupRouter struct {
+}
+
+func (b *BackupRouter) InitBackupRouter(Router *gin.RouterGroup) {
+	backupRouter := Router.Group("backup")
+
+	backupApi := api.ApiGroupApp.BackupApi
+	{
+		backupRouter.GET("list", backupApi.BackupList)
+	}
+}
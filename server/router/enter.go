@@ -12,6 +12,10 @@ type RouterGroup struct {
 	WebsiteRouter
 	ConfigRouter
 	AIRouter
+	SitemapRouter
+	SeriesRouter
+	BackupRouter
+	TwoFactorRouter
 }
 
 var RouterGroupApp = new(RouterGroup)
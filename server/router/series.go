@@ -0,0 +1,26 @@
+package router
+
+import (
+	"server/api"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SeriesRouter struct {
+}
+
+func (s *SeriesRouter) InitSeriesRouter(Router *gin.RouterGroup, PublicRouter *gin.RouterGroup) {
+	seriesRouter := Router.Group("series")
+	seriesPublicRouter := PublicRouter.Group("series")
+
+	seriesApi := api.ApiGroupApp.SeriesApi
+	{
+		seriesRouter.POST("create", seriesApi.SeriesCreate)
+		seriesRouter.DELETE("delete", seriesApi.SeriesDelete)
+		seriesRouter.PUT("update", seriesApi.SeriesUpdate)
+		seriesRouter.GET("list", seriesApi.SeriesList)
+	}
+	{
+		seriesPublicRouter.GET(":id", seriesApi.SeriesInfo)
+	}
+}
@@ -0,0 +1,20 @@
+package router
+
+import (
+	"server/api"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SitemapRouter struct {
+}
+
+// InitSitemapRouter 站点地图和 robots.txt 需要挂载在网站根路径下，不使用路由前缀
+func (s *SitemapRouter) InitSitemapRouter(Router *gin.RouterGroup) {
+	sitemapApi := api.ApiGroupApp.SitemapApi
+	{
+		Router.GET("sitemap.xml", sitemapApi.SitemapIndex)
+		Router.GET("sitemap/:name", sitemapApi.Sitemap)
+		Router.GET("robots.txt", sitemapApi.Robots)
+	}
+}
@@ -0,0 +1,23 @@
+package router
+
+import (
+	"server/api"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TwoFactorRouter struct {
+}
+
+func (t *TwoFactorRouter) InitTwoFactorRouter(Router *gin.RouterGroup) {
+	twoFactorRouter := Router.Group("twoFactor")
+
+	twoFactorApi := api.ApiGroupApp.TwoFactorApi
+	{
+		twoFactorRouter.GET("status", twoFactorApi.TwoFactorStatus)
+		twoFactorRouter.POST("enroll", twoFactorApi.TwoFactorEnroll)
+		twoFactorRouter.POST("confirm", twoFactorApi.TwoFactorConfirm)
+		twoFactorRouter.POST("recoveryCodes", twoFactorApi.TwoFactorRecoveryCodes)
+		twoFactorRouter.POST("disable", twoFactorApi.TwoFactorDisable)
+	}
+}
@@ -21,8 +21,13 @@ func (u *UserRouter) InitUserRouter(Router *gin.RouterGroup, PublicRouter *gin.R
 		userRouter.PUT("resetPassword", userApi.UserResetPassword)
 		userRouter.GET("info", userApi.UserInfo)
 		userRouter.PUT("changeInfo", userApi.UserChangeInfo)
+		userRouter.PUT("changeEmail", userApi.UserChangeEmail)
+		userRouter.DELETE("deleteAccount", userApi.UserDeleteAccount)
 		userRouter.GET("weather", userApi.UserWeather)
 		userRouter.GET("chart", userApi.UserChart)
+		userRouter.GET("sessions", userApi.UserSessionList)
+		userRouter.DELETE("session", userApi.UserSessionRevoke)
+		userRouter.DELETE("sessions/others", userApi.UserSessionRevokeOthers)
 	}
 	{
 		userPublicRouter.POST("forgotPassword", userApi.ForgotPassword)
@@ -31,11 +36,13 @@ func (u *UserRouter) InitUserRouter(Router *gin.RouterGroup, PublicRouter *gin.R
 	{
 		userLoginRouter.POST("register", userApi.Register)
 		userLoginRouter.POST("login", userApi.Login)
+		userLoginRouter.POST("twoFactorLogin", userApi.TwoFactorLogin)
 	}
 	{
 		userAdminRouter.GET("list", userApi.UserList)
 		userAdminRouter.PUT("freeze", userApi.UserFreeze)
 		userAdminRouter.PUT("unfreeze", userApi.UserUnfreeze)
 		userAdminRouter.GET("loginList", userApi.UserLoginList)
+		userAdminRouter.PUT("revokeSessions", userApi.UserRevokeSessions)
 	}
 }
@@ -28,5 +28,8 @@ func (w *WebsiteRouter) InitWebsiteRouter(Router *gin.RouterGroup, PublicRouter
 		websitePublicRouter.GET("news", websiteApi.WebsiteNews)
 		websitePublicRouter.GET("calendar", websiteApi.WebsiteCalendar)
 		websitePublicRouter.GET("footerLink", websiteApi.WebsiteFooterLink)
+		websitePublicRouter.GET("rss", websiteApi.WebsiteRSS)
+		websitePublicRouter.GET("atom", websiteApi.WebsiteAtom)
+		websitePublicRouter.GET("feed", websiteApi.WebsiteJSONFeed)
 	}
 }
@@ -9,13 +9,12 @@ import (
 	"server/model/elasticsearch"
 	"server/model/other"
 	"server/model/request"
+	"server/model/response"
 	"server/utils"
-	"strconv"
 	"time"
 
 	"github.com/elastic/go-elasticsearch/v8/typedapi/core/search"
 	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
-	"github.com/elastic/go-elasticsearch/v8/typedapi/types/enums/scriptlanguage"
 	"github.com/elastic/go-elasticsearch/v8/typedapi/types/enums/sortorder"
 	"gorm.io/gorm"
 )
@@ -23,52 +22,129 @@ import (
 type ArticleService struct {
 }
 
-func (articleService *ArticleService) ArticleInfoByID(id string) (elasticsearch.Article, error) {
-	// 异步更新浏览量
-	go func() {
-		articleView := articleService.NewArticleView()
-		_ = articleView.Set(id)
-	}()
-	return articleService.Get(id)
+func (articleService *ArticleService) ArticleInfoByID(id string) (response.ArticleInfo, error) {
+	article, err := articleService.Get(id)
+	if err != nil {
+		return response.ArticleInfo{}, err
+	}
+	// 未发布的文章对外不可见
+	if article.Status != appTypes.Published {
+		return response.ArticleInfo{}, errors.New("document not found")
+	}
+
+	// 早期的文章没有保存渲染结果，读取时渲染
+	if article.ContentHTML == "" && article.Content != "" {
+		rendered, err := utils.RenderMarkdown(article.Content)
+		if err != nil {
+			return response.ArticleInfo{}, err
+		}
+		article.ContentHTML = rendered.HTML
+		article.Toc = rendered.Toc
+		article.WordCount = rendered.WordCount
+		article.ReadingTime = rendered.ReadingTime
+	}
+
+	// 文章所在系列中的上一篇和下一篇
+	series, err := ServiceGroupApp.SeriesService.ArticleSeriesNav(id)
+	if err != nil {
+		return response.ArticleInfo{}, err
+	}
+
+	return response.ArticleInfo{Article: article, Series: series}, nil
 }
 
-func (articleService *ArticleService) ArticleSearch(info request.ArticleSearch) (interface{}, int64, error) {
+func (articleService *ArticleService) ArticleSearch(info request.ArticleSearch) (interface{}, int64, response.ArticleFacets, error) {
 	req := &search.Request{
 		Query: &types.Query{},
 	}
 
-	boolQuery := &types.BoolQuery{}
+	boolQuery := &types.BoolQuery{
+		// 只搜索已发布的文章
+		Filter: []types.Query{publishedQuery()},
+	}
 
-	// 根据查询字段查询
+	// 根据查询字段查询，允许拼写错误的模糊匹配，精确匹配的得分更高
 	if info.Query != "" {
+		titleBoost := float32(2)
 		boolQuery.Should = []types.Query{
-			{Match: map[string]types.MatchQuery{"title": {Query: info.Query}}},
+			{Match: map[string]types.MatchQuery{"title": {Query: info.Query, Boost: &titleBoost}}},
 			{Match: map[string]types.MatchQuery{"keyword": {Query: info.Query}}},
 			{Match: map[string]types.MatchQuery{"abstract": {Query: info.Query}}},
 			{Match: map[string]types.MatchQuery{"content": {Query: info.Query}}},
+			{MultiMatch: &types.MultiMatchQuery{
+				Query:        info.Query,
+				Fields:       []string{"title", "abstract", "content"},
+				Fuzziness:    "AUTO",
+				PrefixLength: &fuzzyPrefixLength,
+			}},
 		}
+		// 存在过滤条件时，至少需要匹配一个查询字段
+		boolQuery.MinimumShouldMatch = 1
+
+		// 高亮标题、简介和内容中匹配的部分
+		req.Highlight = searchHighlight()
 	}
 
-	// 根据标签筛选
+	// 类别和标签的筛选条件放在 post_filter 中，使统计结果不会只剩下已选中的类别和标签
+	// 根据标签筛选，and 模式需要包含所有标签，or 模式包含任意一个标签即可
+	var tagFilters []types.Query
+	tags := info.Tags
 	if info.Tag != "" {
-		boolQuery.Must = []types.Query{
-			{Match: map[string]types.MatchQuery{"tags": {Query: info.Tag}}},
+		tags = append(tags, info.Tag)
+	}
+	if len(tags) > 0 {
+		if info.TagMode == "and" {
+			for _, tag := range tags {
+				tagFilters = append(tagFilters, types.Query{Term: map[string]types.TermQuery{"tags": {Value: tag}}})
+			}
+		} else {
+			tagFilters = append(tagFilters, termsQuery("tags", tags))
 		}
 	}
 
-	// 根据类别筛选
+	// 根据类别筛选，匹配任意一个类别即可
+	var categoryFilters []types.Query
+	categories := info.Categories
 	if info.Category != "" {
-		boolQuery.Filter = []types.Query{
-			{Term: map[string]types.TermQuery{"category": {Value: info.Category}}},
+		categories = append(categories, info.Category)
+	}
+	if len(categories) > 0 {
+		categoryFilters = append(categoryFilters, termsQuery("category", categories))
+	}
+	if len(tagFilters)+len(categoryFilters) > 0 {
+		req.PostFilter = filterQuery(categoryFilters, tagFilters)
+	}
+
+	// 根据创建时间筛选，结束日期包含当天
+	if info.StartDate != "" || info.EndDate != "" {
+		dateRange := types.DateRangeQuery{Format: strPtr("yyyy-MM-dd")}
+		if info.StartDate != "" {
+			dateRange.Gte = &info.StartDate
 		}
+		if info.EndDate != "" {
+			endDate := info.EndDate + "||/d"
+			dateRange.Lte = &endDate
+		}
+		boolQuery.Filter = append(boolQuery.Filter, types.Query{Range: map[string]types.RangeQuery{"created_at": dateRange}})
 	}
 
-	// 如果有查询条件，则使用 Bool 查询，否则使用 MatchAll 查询
-	if boolQuery.Should != nil || boolQuery.Must != nil || boolQuery.Filter != nil {
-		req.Query.Bool = boolQuery
-	} else {
-		req.Query.MatchAll = &types.MatchAllQuery{}
+	// 根据最少浏览量和收藏量筛选
+	if info.MinViews > 0 {
+		minViews := types.Float64(info.MinViews)
+		boolQuery.Filter = append(boolQuery.Filter, types.Query{Range: map[string]types.RangeQuery{"views": types.NumberRangeQuery{Gte: &minViews}}})
 	}
+	if info.MinLikes > 0 {
+		minLikes := types.Float64(info.MinLikes)
+		boolQuery.Filter = append(boolQuery.Filter, types.Query{Range: map[string]types.RangeQuery{"likes": types.NumberRangeQuery{Gte: &minLikes}}})
+	}
+
+	req.Query.Bool = boolQuery
+
+	// 在同一次查询中统计各个类别、标签和月份的文章数量
+	req.Aggregations = facetAggregations(categoryFilters, tagFilters)
+
+	// 置顶的文章始终排在最前面，按置顶顺序排列
+	req.Sort = pinnedSort()
 
 	// 设置排序字段
 	if info.Sort != "" {
@@ -93,22 +169,30 @@ func (articleService *ArticleService) ArticleSearch(info request.ArticleSearch)
 			order = sortorder.Asc
 		}
 
-		req.Sort = []types.SortCombinations{
-			types.SortOptions{
-				SortOptions: map[string]types.FieldSort{
-					sortField: {Order: &order},
-				},
+		req.Sort = append(req.Sort, types.SortOptions{
+			SortOptions: map[string]types.FieldSort{
+				sortField: {Order: &order},
 			},
-		}
+		})
+	} else {
+		req.Sort = append(req.Sort, types.SortOptions{
+			SortOptions: map[string]types.FieldSort{
+				"_score": {Order: &sortorder.Desc},
+			},
+		})
 	}
 
 	option := other.EsOption{
 		PageInfo:       info.PageInfo,
 		Index:          elasticsearch.ArticleIndex(),
 		Request:        req,
-		SourceIncludes: []string{"created_at", "cover", "title", "abstract", "category", "tags", "views", "comments", "likes"},
+		SourceIncludes: []string{"created_at", "cover", "title", "slug", "abstract", "category", "tags", "views", "comments", "likes", "pinned", "pin_order", "featured"},
 	}
-	return utils.EsPagination(context.TODO(), option)
+	res, err := utils.EsSearch(context.TODO(), option)
+	if err != nil {
+		return nil, 0, response.ArticleFacets{}, err
+	}
+	return res.Hits.Hits, res.Hits.Total.Value, parseFacets(res.Aggregations), nil
 }
 
 func (articleService *ArticleService) ArticleCategory() ([]database.ArticleCategory, error) {
@@ -145,11 +229,8 @@ func (articleService *ArticleService) ArticleLike(req request.ArticleLike) error
 			num = -1
 		}
 
-		// 更新文章收藏数
-		source := "ctx._source.likes += " + strconv.Itoa(num)
-		script := types.Script{Source: &source, Lang: &scriptlanguage.Painless}
-		_, err := global.ESClient.Update(elasticsearch.ArticleIndex(), req.ArticleID).Script(&script).Do(context.TODO())
-		return err
+		// 文章收藏数由后台任务同步到 ES
+		return enqueueCounter(tx, req.ArticleID, "likes", num)
 	})
 }
 
@@ -193,6 +274,9 @@ func (articleService *ArticleService) ArticleLikesList(info request.ArticleLikes
 }
 
 func (articleService *ArticleService) ArticleCreate(req request.ArticleCreate) error {
+	if err := checkStatus(req.Status, req.PublishAt); err != nil {
+		return err
+	}
 	b, err := articleService.Exits(req.Title)
 	if err != nil {
 		return err
@@ -200,26 +284,54 @@ func (articleService *ArticleService) ArticleCreate(req request.ArticleCreate) e
 	if b {
 		return errors.New("the article already exists")
 	}
+	slug, err := articleService.GenerateUniqueSlug(req.Title, "")
+	if err != nil {
+		return err
+	}
+	rendered, err := utils.RenderMarkdown(req.Content)
+	if err != nil {
+		return err
+	}
 	now := time.Now().Format("2006-01-02 15:04:05")
+	// 导入的文章保留原有的创建时间
+	createdAt := now
+	if req.CreatedAt != "" {
+		createdAt = req.CreatedAt
+	}
 	articleToCreate := elasticsearch.Article{
-		CreatedAt: now,
+		CreatedAt: createdAt,
 		UpdatedAt: now,
 		Cover:     req.Cover,
 		Title:     req.Title,
 		Keyword:   req.Title,
+		Slug:      slug,
 		Category:  req.Category,
 		Tags:      req.Tags,
 		Abstract:  req.Abstract,
 		Content:   req.Content,
+		Suggest:   articleSuggest(req.Title, req.Tags),
+
+		ContentHTML: rendered.HTML,
+		Toc:         rendered.Toc,
+		WordCount:   rendered.WordCount,
+		ReadingTime: rendered.ReadingTime,
+
+		Status: req.Status,
 	}
-	return global.DB.Transaction(func(tx *gorm.DB) error {
-		// 同时更新文章类别表中的数据
-		if err := articleService.UpdateCategoryCount(tx, "", articleToCreate.Category); err != nil {
+	// 导入的文章保留原有的发布时间，未指定时以创建时间作为发布时间
+	articleToCreate.PublishAt = req.PublishAt
+	if req.Status == appTypes.Published && req.PublishAt == "" {
+		articleToCreate.PublishAt = createdAt
+	}
+	if err := global.DB.Transaction(func(tx *gorm.DB) error {
+		// 同时更新文章类别表中的数据，只统计已发布的文章
+		category, tags := visibleTaxonomy(articleToCreate)
+		if err := articleService.UpdateCategoryCount(tx, "", category); err != nil {
 			return err
 		}
 
 		// 同时更新文章标签表中的数据
-		if err := articleService.UpdateTagsCount(tx, []string{}, articleToCreate.Tags); err != nil {
+		if err := articleService.UpdateTagsCount(tx, []string{}, tags); err != nil {
 			return err
 		}
 
@@ -236,27 +348,32 @@ func (articleService *ArticleService) ArticleCreate(req request.ArticleCreate) e
 		}
 
 		return articleService.Create(&articleToCreate)
-	})
+	}); err != nil {
+		return err
+	}
+	articleService.articleChanged()
+	return nil
 }
 
 func (articleService *ArticleService) ArticleDelete(req request.ArticleDelete) error {
 	if len(req.IDs) == 0 {
 		return nil
 	}
-	return global.DB.Transaction(func(tx *gorm.DB) error {
+	if err := global.DB.Transaction(func(tx *gorm.DB) error {
 		for _, id := range req.IDs {
 			articleToDelete, err := articleService.Get(id)
 			if err != nil {
 				return err
 			}
 
-			// 同时更新文章类别表中的数据
-			if err := articleService.UpdateCategoryCount(tx, articleToDelete.Category, ""); err != nil {
+			// 同时更新文章类别表中的数据，只有已发布的文章计入了统计
+			category, tags := visibleTaxonomy(articleToDelete)
+			if err := articleService.UpdateCategoryCount(tx, category, ""); err != nil {
 				return err
 			}
 
 			// 同时更新文章标签表中的数据
-			if err := articleService.UpdateTagsCount(tx, articleToDelete.Tags, []string{}); err != nil {
+			if err := articleService.UpdateTagsCount(tx, tags, []string{}); err != nil {
 				return err
 			}
 
@@ -281,45 +398,115 @@ func (articleService *ArticleService) ArticleDelete(req request.ArticleDelete) e
 					return err
 				}
 			}
+			// 同时删除该文章的所有历史版本
+			if err := tx.Where("article_id = ?", id).Delete(&database.ArticleRevision{}).Error; err != nil {
+				return err
+			}
+			// 同时删除该文章的所有历史 slug
+			if err := tx.Unscoped().Where("article_id = ?", id).Delete(&database.ArticleSlug{}).Error; err != nil {
+				return err
+			}
+			// 同时将该文章从所有系列中移除
+			if err := ServiceGroupApp.SeriesService.RemoveArticle(tx, id); err != nil {
+				return err
+			}
 		}
 		return articleService.Delete(req.IDs)
-	})
+	}); err != nil {
+		return err
+	}
+	articleService.articleChanged()
+	return nil
 }
 
 func (articleService *ArticleService) ArticleUpdate(req request.ArticleUpdate) error {
+	rendered, err := utils.RenderMarkdown(req.Content)
+	if err != nil {
+		return err
+	}
 	now := time.Now().Format("2006-01-02 15:04:05")
 	articleToUpdate := struct {
-		UpdatedAt string   `json:"updated_at"`
-		Cover     string   `json:"cover"`
-		Title     string   `json:"title"`
-		Keyword   string   `json:"keyword"`
-		Category  string   `json:"category"`
-		Tags      []string `json:"tags"`
-		Abstract  string   `json:"abstract"`
-		Content   string   `json:"content"`
+		UpdatedAt   string          `json:"updated_at"`
+		Cover       string          `json:"cover"`
+		Title       string          `json:"title"`
+		Keyword     string          `json:"keyword"`
+		Slug        string          `json:"slug"`
+		Category    string          `json:"category"`
+		Tags        []string        `json:"tags"`
+		Abstract    string          `json:"abstract"`
+		Content     string          `json:"content"`
+		Suggest     []string        `json:"suggest"`
+		ContentHTML string          `json:"content_html"`
+		Toc         []other.TocItem `json:"toc"`
+		WordCount   int             `json:"word_count"`
+		ReadingTime int             `json:"reading_time"`
 	}{
-		UpdatedAt: now,
-		Cover:     req.Cover,
-		Title:     req.Title,
-		Keyword:   req.Title,
-		Category:  req.Category,
-		Tags:      req.Tags,
-		Abstract:  req.Abstract,
-		Content:   req.Content,
+		UpdatedAt:   now,
+		Cover:       req.Cover,
+		Title:       req.Title,
+		Keyword:     req.Title,
+		Category:    req.Category,
+		Tags:        req.Tags,
+		Abstract:    req.Abstract,
+		Content:     req.Content,
+		Suggest:     articleSuggest(req.Title, req.Tags),
+		ContentHTML: rendered.HTML,
+		Toc:         rendered.Toc,
+		WordCount:   rendered.WordCount,
+		ReadingTime: rendered.ReadingTime,
 	}
-	return global.DB.Transaction(func(tx *gorm.DB) error {
+	if err := global.DB.Transaction(func(tx *gorm.DB) error {
 		oldArticle, err := articleService.Get(req.ID)
 		if err != nil {
 			return err
 		}
 
-		// 同时更新文章类别表中的数据
-		if err := articleService.UpdateCategoryCount(tx, oldArticle.Category, articleToUpdate.Category); err != nil {
+		// 保存被替换的旧版本，以便查看历史和回滚
+		if err := tx.Create(&database.ArticleRevision{
+			ArticleID:    req.ID,
+			Title:        oldArticle.Title,
+			Abstract:     oldArticle.Abstract,
+			Content:      oldArticle.Content,
+			Tags:         oldArticle.Tags,
+			Category:     oldArticle.Category,
+			Cover:        oldArticle.Cover,
+			VersionAt:    oldArticle.UpdatedAt,
+			ReplacedByID: req.EditorID,
+		}).Error; err != nil {
+			return err
+		}
+
+		// 标题修改后重新生成 slug，旧的 slug 保存到历史中用于重定向
+		articleToUpdate.Slug = oldArticle.Slug
+		if articleToUpdate.Title != oldArticle.Title || oldArticle.Slug == "" {
+			slug, err := articleService.GenerateUniqueSlug(articleToUpdate.Title, req.ID)
+			if err != nil {
+				return err
+			}
+			if oldArticle.Slug != "" && oldArticle.Slug != slug {
+				if err := tx.Create(&database.ArticleSlug{Slug: oldArticle.Slug, ArticleID: req.ID}).Error; err != nil {
+					return err
+				}
+			}
+			// 新的 slug 可能是该文章曾经使用过的，从历史中移除
+			if err := tx.Unscoped().Where("slug = ?", slug).Delete(&database.ArticleSlug{}).Error; err != nil {
+				return err
+			}
+			articleToUpdate.Slug = slug
+		}
+
+		// 同时更新文章类别表中的数据，文章状态不变，只有已发布的文章计入统计
+		newArticle := oldArticle
+		newArticle.Category = articleToUpdate.Category
+		newArticle.Tags = articleToUpdate.Tags
+		oldCategory, oldTags := visibleTaxonomy(oldArticle)
+		newCategory, newTags := visibleTaxonomy(newArticle)
+		if err := articleService.UpdateCategoryCount(tx, oldCategory, newCategory); err != nil {
 			return err
 		}
 
 		// 同时更新文章标签表中的数据
-		if err := articleService.UpdateTagsCount(tx, oldArticle.Tags, articleToUpdate.Tags); err != nil {
+		if err := articleService.UpdateTagsCount(tx, oldTags, newTags); err != nil {
 			return err
 		}
 
@@ -349,7 +536,48 @@ func (articleService *ArticleService) ArticleUpdate(req request.ArticleUpdate) e
 		}
 
 		return articleService.Update(req.ID, articleToUpdate)
-	})
+	}); err != nil {
+		return err
+	}
+	articleService.articleChanged()
+	return nil
+}
+
+func (articleService *ArticleService) ArticleStatusUpdate(req request.ArticleStatusUpdate) error {
+	if err := checkStatus(req.Status, req.PublishAt); err != nil {
+		return err
+	}
+	if err := global.DB.Transaction(func(tx *gorm.DB) error {
+		oldArticle, err := articleService.Get(req.ID)
+		if err != nil {
+			return err
+		}
+
+		publishAt := ""
+		if req.Status == appTypes.Scheduled {
+			publishAt = req.PublishAt
+		}
+		return articleService.UpdateStatus(tx, req.ID, oldArticle, req.Status, publishAt)
+	}); err != nil {
+		return err
+	}
+	articleService.articleChanged()
+	return nil
+}
+
+// ArticlePublishScheduled 发布已到发布时间的定时发布文章，同时更新文章类别和标签的计数
+func (articleService *ArticleService) ArticlePublishScheduled(id string, article elasticsearch.Article) error {
+	if err := global.DB.Transaction(func(tx *gorm.DB) error {
+		return articleService.UpdateStatus(tx, id, article, appTypes.Published, article.PublishAt)
+	}); err != nil {
+		return err
+	}
+	articleService.articleChanged()
+	return nil
+}
+
+func (articleService *ArticleService) ArticleDetail(id string) (elasticsearch.Article, error) {
+	return articleService.Get(id)
 }
 
 func (articleService *ArticleService) ArticleList(info request.ArticleList) (list interface{}, total int64, err error) {
@@ -380,6 +608,15 @@ func (articleService *ArticleService) ArticleList(info request.ArticleList) (lis
 		}
 	}
 
+	// 根据状态筛选
+	if info.Status != nil {
+		if appTypes.ToArticleStatus(*info.Status) == appTypes.Published {
+			boolQuery.Filter = append(boolQuery.Filter, publishedQuery())
+		} else {
+			boolQuery.Filter = append(boolQuery.Filter, types.Query{Term: map[string]types.TermQuery{"status": {Value: *info.Status}}})
+		}
+	}
+
 	// 根据条件执行查询
 	if boolQuery.Must != nil || boolQuery.Filter != nil {
 		req.Query.Bool = boolQuery
@@ -0,0 +1,262 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"server/global"
+	"server/model/database"
+	"server/model/elasticsearch"
+	"time"
+
+	"github.com/elastic/go-elasticsearch/v8/typedapi/core/bulk"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types/enums/scriptlanguage"
+	"gorm.io/gorm"
+)
+
+const (
+	outboxLockName   = "es_outbox"     // 同步与校正计数时使用的 MySQL 命名锁
+	outboxBatchSize  = 500             // 每次同步的最大变更数量
+	outboxMaxBackoff = time.Hour       // 同步失败后的最大重试间隔
+	outboxBaseDelay  = 5 * time.Second // 同步失败后的初始重试间隔
+)
+
+// counterScript 按变化量更新计数字段，字段不存在时从 0 开始
+const counterScript = "if (ctx._source[params.field] == null) { ctx._source[params.field] = 0 } ctx._source[params.field] += params.delta"
+
+// enqueueCounter 在事务中记录文章计数的变化，由后台任务同步到 ES
+func enqueueCounter(tx *gorm.DB, articleID, field string, delta int) error {
+	return tx.Create(&database.EsOutbox{
+		ArticleID:   articleID,
+		Field:       field,
+		Delta:       delta,
+		NextRetryAt: time.Now(),
+	}).Error
+}
+
+// withOutboxLock 在持有 MySQL 命名锁期间执行 fn，避免多个实例同时同步或校正计数，timeout 秒内未获得锁时返回 false
+func withOutboxLock(timeout int, fn func() error) (bool, error) {
+	sqlDB, err := global.DB.DB()
+	if err != nil {
+		return false, err
+	}
+	conn, err := sqlDB.Conn(context.TODO())
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close()
+
+	var locked sql.NullInt64
+	if err := conn.QueryRowContext(context.TODO(), "SELECT GET_LOCK(?, ?)", outboxLockName, timeout).Scan(&locked); err != nil {
+		return false, err
+	}
+	if locked.Int64 != 1 {
+		return false, nil
+	}
+	defer func() {
+		_, _ = conn.ExecContext(context.TODO(), "SELECT RELEASE_LOCK(?)", outboxLockName)
+	}()
+	return true, fn()
+}
+
+// SyncCounters 将 outbox 中到期的计数变更合并后批量同步到 ES，失败的变更按指数退避重试，返回同步成功的变更数量
+func (articleService *ArticleService) SyncCounters() (int, error) {
+	synced := 0
+	_, err := withOutboxLock(0, func() error {
+		var events []database.EsOutbox
+		if err := global.DB.Where("next_retry_at <= ?", time.Now()).Order("id").Limit(outboxBatchSize).Find(&events).Error; err != nil {
+			return err
+		}
+		if len(events) == 0 {
+			return nil
+		}
+
+		// 合并同一文章同一字段的变更
+		type counterKey struct{ articleID, field string }
+		deltas := make(map[counterKey]int)
+		eventIDs := make(map[counterKey][]uint)
+		var keys []counterKey
+		for _, event := range events {
+			key := counterKey{event.ArticleID, event.Field}
+			if _, exists := deltas[key]; !exists {
+				keys = append(keys, key)
+			}
+			deltas[key] += event.Delta
+			eventIDs[key] = append(eventIDs[key], event.ID)
+		}
+
+		var request bulk.Request
+		for _, key := range keys {
+			id := key.articleID
+			params := map[string]json.RawMessage{
+				"field": json.RawMessage(fmt.Sprintf("%q", key.field)),
+				"delta": json.RawMessage(fmt.Sprint(deltas[key])),
+			}
+			source := counterScript
+			request = append(request,
+				types.OperationContainer{Update: &types.UpdateOperation{Id_: &id}},
+				types.UpdateAction{Script: &types.Script{Source: &source, Lang: &scriptlanguage.Painless, Params: params}},
+			)
+		}
+		res, err := global.ESClient.Bulk().Index(elasticsearch.ArticleIndex()).Request(&request).Do(context.TODO())
+		if err != nil {
+			if retryErr := outboxRetry(events, err.Error()); retryErr != nil {
+				return retryErr
+			}
+			return err
+		}
+
+		// 成功或文章已被删除的变更直接移除，其余的变更稍后重试
+		var done []uint
+		var failed []database.EsOutbox
+		var reason string
+		for i, item := range res.Items {
+			key := keys[i]
+			for _, result := range item {
+				if result.Error == nil || result.Status == http.StatusNotFound {
+					done = append(done, eventIDs[key]...)
+					continue
+				}
+				reason = result.Error.Type
+				if result.Error.Reason != nil {
+					reason = *result.Error.Reason
+				}
+				for _, event := range events {
+					if event.ArticleID == key.articleID && event.Field == key.field {
+						failed = append(failed, event)
+					}
+				}
+			}
+		}
+		if len(done) > 0 {
+			if err := global.DB.Unscoped().Delete(&database.EsOutbox{}, done).Error; err != nil {
+				return err
+			}
+		}
+		synced = len(done)
+		if len(failed) > 0 {
+			if err := outboxRetry(failed, reason); err != nil {
+				return err
+			}
+			return fmt.Errorf("failed to sync %d article counter changes: %s", len(failed), reason)
+		}
+		return nil
+	})
+	return synced, err
+}
+
+// outboxRetry 记录同步失败的原因，并按指数退避推迟下次同步的时间
+func outboxRetry(events []database.EsOutbox, reason string) error {
+	for _, event := range events {
+		delay := outboxBaseDelay << min(event.Attempts, 10)
+		if delay > outboxMaxBackoff {
+			delay = outboxMaxBackoff
+		}
+		err := global.DB.Model(&event).Updates(map[string]interface{}{
+			"attempts":      event.Attempts + 1,
+			"next_retry_at": time.Now().Add(delay),
+			"last_error":    reason,
+		}).Error
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReconcileCounters 根据 MySQL 中的评论和收藏重新计算 ES 中所有文章的 comments 和 likes，返回被修正的文章数量
+// outbox 中尚未同步的变更会从目标值中扣除，之后由后台任务继续同步
+func (articleService *ArticleService) ReconcileCounters() (int, error) {
+	fixed := 0
+	locked, err := withOutboxLock(30, func() error {
+		comments := make(map[string]int)
+		likes := make(map[string]int)
+		pending := make(map[string]map[string]int)
+
+		// 在同一个快照中读取计数和未同步的变更
+		err := global.DB.Transaction(func(tx *gorm.DB) error {
+			var counts []struct {
+				ArticleID string
+				Count     int
+			}
+			if err := tx.Model(&database.Comment{}).Select("article_id, COUNT(*) AS count").Group("article_id").Scan(&counts).Error; err != nil {
+				return err
+			}
+			for _, count := range counts {
+				comments[count.ArticleID] = count.Count
+			}
+			counts = nil
+			if err := tx.Model(&database.ArticleLike{}).Select("article_id, COUNT(*) AS count").Group("article_id").Scan(&counts).Error; err != nil {
+				return err
+			}
+			for _, count := range counts {
+				likes[count.ArticleID] = count.Count
+			}
+
+			var sums []struct {
+				ArticleID string
+				Field     string
+				Delta     int
+			}
+			if err := tx.Model(&database.EsOutbox{}).Select("article_id, field, SUM(delta) AS delta").Group("article_id, field").Scan(&sums).Error; err != nil {
+				return err
+			}
+			for _, sum := range sums {
+				if pending[sum.ArticleID] == nil {
+					pending[sum.ArticleID] = make(map[string]int)
+				}
+				pending[sum.ArticleID][sum.Field] = sum.Delta
+			}
+			return nil
+		}, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+		if err != nil {
+			return err
+		}
+
+		var request bulk.Request
+		flush := func() error {
+			if len(request) == 0 {
+				return nil
+			}
+			res, err := global.ESClient.Bulk().Index(elasticsearch.ArticleIndex()).Request(&request).Do(context.TODO())
+			if err != nil {
+				return err
+			}
+			if res.Errors {
+				return errors.New("failed to update some article counters")
+			}
+			request = nil
+			return nil
+		}
+		err = articleService.Scan(&types.Query{MatchAll: &types.MatchAllQuery{}}, []string{"comments", "likes"}, func(id string, article elasticsearch.Article) error {
+			targetComments := comments[id] - pending[id]["comments"]
+			targetLikes := likes[id] - pending[id]["likes"]
+			if article.Comments == targetComments && article.Likes == targetLikes {
+				return nil
+			}
+			doc, err := json.Marshal(map[string]int{"comments": targetComments, "likes": targetLikes})
+			if err != nil {
+				return err
+			}
+			articleID := id
+			request = append(request, types.OperationContainer{Update: &types.UpdateOperation{Id_: &articleID}}, types.UpdateAction{Doc: doc})
+			fixed++
+			if len(request) >= 2*global.Config.ES.Batch() {
+				return flush()
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		return flush()
+	})
+	if err == nil && !locked {
+		err = errors.New("timed out waiting for the article counter sync to finish")
+	}
+	return fixed, err
+}
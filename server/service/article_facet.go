@@ -0,0 +1,92 @@
+package service
+
+import (
+	"fmt"
+	"server/model/response"
+
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types/enums/calendarinterval"
+)
+
+// facetSize 类别和标签统计返回的最大数量
+const facetSize = 50
+
+// facetAggregations 返回按类别、标签和月份统计文章数量的聚合
+// 每个统计只应用其他维度的筛选条件，选中类别后仍能看到其他类别的数量，便于切换和多选
+func facetAggregations(categoryFilters, tagFilters []types.Query) map[string]types.Aggregations {
+	size := facetSize
+	minDocCount := 1
+	interval := calendarinterval.Month
+	return map[string]types.Aggregations{
+		"categories": {
+			Filter:       filterQuery(tagFilters),
+			Aggregations: map[string]types.Aggregations{"facet": {Terms: &types.TermsAggregation{Field: strPtr("category"), Size: &size}}},
+		},
+		"tags": {
+			Filter:       filterQuery(categoryFilters),
+			Aggregations: map[string]types.Aggregations{"facet": {Terms: &types.TermsAggregation{Field: strPtr("tags"), Size: &size}}},
+		},
+		"months": {
+			Filter: filterQuery(categoryFilters, tagFilters),
+			Aggregations: map[string]types.Aggregations{"facet": {DateHistogram: &types.DateHistogramAggregation{
+				Field:            strPtr("created_at"),
+				CalendarInterval: &interval,
+				Format:           strPtr("yyyy-MM"),
+				MinDocCount:      &minDocCount,
+			}}},
+		},
+	}
+}
+
+// filterQuery 将多组筛选条件合并为一个查询，没有筛选条件时匹配所有文章
+func filterQuery(filters ...[]types.Query) *types.Query {
+	boolQuery := &types.BoolQuery{Filter: []types.Query{}}
+	for _, f := range filters {
+		boolQuery.Filter = append(boolQuery.Filter, f...)
+	}
+	return &types.Query{Bool: boolQuery}
+}
+
+// parseFacets 解析聚合结果，月份按时间倒序排列
+func parseFacets(aggregations map[string]types.Aggregate) response.ArticleFacets {
+	facets := response.ArticleFacets{
+		Categories: termsFacet(facetAggregate(aggregations, "categories")),
+		Tags:       termsFacet(facetAggregate(aggregations, "tags")),
+		Months:     []response.FacetCount{},
+	}
+	if histogram, ok := facetAggregate(aggregations, "months").(*types.DateHistogramAggregate); ok {
+		if buckets, ok := histogram.Buckets.([]types.DateHistogramBucket); ok {
+			for i := len(buckets) - 1; i >= 0; i-- {
+				key := fmt.Sprint(buckets[i].Key)
+				if buckets[i].KeyAsString != nil {
+					key = *buckets[i].KeyAsString
+				}
+				facets.Months = append(facets.Months, response.FacetCount{Key: key, Count: buckets[i].DocCount})
+			}
+		}
+	}
+	return facets
+}
+
+// facetAggregate 取出 filter 聚合中的统计结果
+func facetAggregate(aggregations map[string]types.Aggregate, name string) types.Aggregate {
+	filter, ok := aggregations[name].(*types.FilterAggregate)
+	if !ok {
+		return nil
+	}
+	return filter.Aggregations["facet"]
+}
+
+func termsFacet(aggregate types.Aggregate) []response.FacetCount {
+	counts := []response.FacetCount{}
+	terms, ok := aggregate.(*types.StringTermsAggregate)
+	if !ok {
+		return counts
+	}
+	if buckets, ok := terms.Buckets.([]types.StringTermsBucket); ok {
+		for _, bucket := range buckets {
+			counts = append(counts, response.FacetCount{Key: fmt.Sprint(bucket.Key), Count: bucket.DocCount})
+		}
+	}
+	return counts
+}
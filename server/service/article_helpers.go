@@ -5,15 +5,21 @@ import (
 	"encoding/json"
 	"errors"
 	"server/global"
+	"server/model/appTypes"
 	"server/model/database"
 	"server/model/elasticsearch"
 	"server/utils"
+	"time"
 
 	"github.com/elastic/go-elasticsearch/v8/typedapi/core/bulk"
 	"github.com/elastic/go-elasticsearch/v8/typedapi/core/search"
 	"github.com/elastic/go-elasticsearch/v8/typedapi/core/update"
 	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types/enums/fieldtype"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types/enums/highlighterencoder"
 	"github.com/elastic/go-elasticsearch/v8/typedapi/types/enums/refresh"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types/enums/sortorder"
+	"go.uber.org/zap"
 	"gorm.io/gorm"
 )
 
@@ -36,6 +42,22 @@ func (articleService *ArticleService) Delete(ids []string) error {
 	return err
 }
 
+// Scan 按创建时间顺序遍历所有符合条件的文章，sourceIncludes 为空时返回文章的全部字段
+func (articleService *ArticleService) Scan(query *types.Query, sourceIncludes []string, fn func(id string, article elasticsearch.Article) error) error {
+	return scanHits(elasticsearch.ArticleIndex(), query, sourceIncludes, func(hits []types.Hit) error {
+		for _, hit := range hits {
+			var article elasticsearch.Article
+			if err := json.Unmarshal(hit.Source_, &article); err != nil {
+				return err
+			}
+			if err := fn(*hit.Id_, article); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
 // Get 用于通过ID从 Elasticsearch 获取文章
 func (articleService *ArticleService) Get(id string) (elasticsearch.Article, error) {
 	var a elasticsearch.Article
@@ -160,3 +182,145 @@ func (articleService *ArticleService) UpdateTagsCount(tx *gorm.DB, oldTags, newT
 	}
 	return nil
 }
+
+// UpdateStatus 修改文章状态，同时根据状态变化维护文章类别和标签的计数
+// 调用方需要在事务提交后调用 articleChanged 清除缓存
+func (articleService *ArticleService) UpdateStatus(tx *gorm.DB, articleID string, oldArticle elasticsearch.Article, status appTypes.ArticleStatus, publishAt string) error {
+	newArticle := oldArticle
+	newArticle.Status = status
+
+	// 已发布的文章需要从统计中移除，新发布的文章需要加入统计
+	oldCategory, oldTags := visibleTaxonomy(oldArticle)
+	newCategory, newTags := visibleTaxonomy(newArticle)
+	if err := articleService.UpdateCategoryCount(tx, oldCategory, newCategory); err != nil {
+		return err
+	}
+	if err := articleService.UpdateTagsCount(tx, oldTags, newTags); err != nil {
+		return err
+	}
+
+	// 发布时未指定发布时间，曾经发布过的文章保留原来的发布时间，否则以当前时间作为发布时间
+	if status == appTypes.Published && publishAt == "" {
+		if oldArticle.PublishAt != "" && oldArticle.Status != appTypes.Scheduled {
+			publishAt = oldArticle.PublishAt
+		} else {
+			publishAt = time.Now().Format("2006-01-02 15:04:05")
+		}
+	}
+
+	articleToUpdate := struct {
+		Status    appTypes.ArticleStatus `json:"status"`
+		PublishAt string                 `json:"publish_at,omitempty"`
+	}{
+		Status:    status,
+		PublishAt: publishAt,
+	}
+	return articleService.Update(articleID, articleToUpdate)
+}
+
+// articleChanged 在文章发生变化后调用，清除依赖文章数据的缓存
+func (articleService *ArticleService) articleChanged() {
+	if err := ServiceGroupApp.SitemapService.ClearSitemapCache(); err != nil {
+		global.Log.Error("Failed to clear sitemap cache:", zap.Error(err))
+	}
+	if err := articleService.ClearRelatedCache(); err != nil {
+		global.Log.Error("Failed to clear related articles cache:", zap.Error(err))
+	}
+}
+
+// checkStatus 校验文章状态和发布时间，定时发布的文章必须指定一个未来的发布时间
+func checkStatus(status appTypes.ArticleStatus, publishAt string) error {
+	switch status {
+	case appTypes.Published, appTypes.Draft, appTypes.Archived:
+		if publishAt == "" {
+			return nil
+		}
+		if _, err := time.ParseInLocation("2006-01-02 15:04:05", publishAt, time.Local); err != nil {
+			return errors.New("invalid publish time, the format should be yyyy-MM-dd HH:mm:ss")
+		}
+		return nil
+	case appTypes.Scheduled:
+		t, err := time.ParseInLocation("2006-01-02 15:04:05", publishAt, time.Local)
+		if err != nil {
+			return errors.New("invalid publish time, the format should be yyyy-MM-dd HH:mm:ss")
+		}
+		if !t.After(time.Now()) {
+			return errors.New("the publish time must be in the future")
+		}
+		return nil
+	default:
+		return errors.New("invalid article status")
+	}
+}
+
+// visibleTaxonomy 返回文章计入统计的类别和标签，只有已发布的文章才会被统计
+func visibleTaxonomy(a elasticsearch.Article) (string, []string) {
+	if a.Status != appTypes.Published {
+		return "", []string{}
+	}
+	return a.Category, a.Tags
+}
+
+// publishedQuery 返回只匹配已发布文章的查询条件，未设置状态的旧文章视为已发布
+func publishedQuery() types.Query {
+	return types.Query{
+		Bool: &types.BoolQuery{
+			Should: []types.Query{
+				{Term: map[string]types.TermQuery{"status": {Value: appTypes.Published.String()}}},
+				{Bool: &types.BoolQuery{MustNot: []types.Query{{Exists: &types.ExistsQuery{Field: "status"}}}}},
+			},
+		},
+	}
+}
+
+// pinnedSort 返回置顶文章优先的排序条件，未设置置顶字段的旧文章视为未置顶，
+// 旧索引的映射中还没有置顶字段时也能正常排序
+func pinnedSort() []types.SortCombinations {
+	return []types.SortCombinations{
+		types.SortOptions{SortOptions: map[string]types.FieldSort{"pinned": {Order: &sortorder.Desc, Missing: "false", UnmappedType: &fieldtype.Boolean}}},
+		types.SortOptions{SortOptions: map[string]types.FieldSort{"pin_order": {Order: &sortorder.Asc, Missing: 0, UnmappedType: &fieldtype.Integer}}},
+	}
+}
+
+// fuzzyPrefixLength 模糊匹配时必须完全一致的前缀长度，减少无关的匹配结果
+var fuzzyPrefixLength = 1
+
+// searchHighlight 返回搜索结果的高亮设置，标题和简介返回完整的高亮文本，内容返回匹配的片段
+func searchHighlight() *types.Highlight {
+	whole := 0
+	fragmentSize := 100
+	fragments := 3
+	encoder := highlighterencoder.Html
+	return &types.Highlight{
+		Encoder:  &encoder,
+		PreTags:  []string{"<mark>"},
+		PostTags: []string{"</mark>"},
+		Fields: map[string]types.HighlightField{
+			"title":    {NumberOfFragments: &whole},
+			"abstract": {NumberOfFragments: &whole},
+			"content":  {FragmentSize: &fragmentSize, NumberOfFragments: &fragments},
+		},
+	}
+}
+
+// articleSuggest 生成文章的搜索建议候选词
+func articleSuggest(title string, tags []string) []string {
+	suggest := []string{title}
+	for _, tag := range tags {
+		if tag != "" && tag != title {
+			suggest = append(suggest, tag)
+		}
+	}
+	return suggest
+}
+
+// termsQuery 返回匹配字段任意一个值的查询条件
+func termsQuery(field string, values []string) types.Query {
+	fieldValues := make([]types.FieldValue, 0, len(values))
+	for _, value := range values {
+		fieldValues = append(fieldValues, value)
+	}
+	return types.Query{Terms: &types.TermsQuery{TermsQuery: map[string]types.TermsQueryField{field: fieldValues}}}
+}
+
+func strPtr(s string) *string { return &s }
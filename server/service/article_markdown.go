@@ -0,0 +1,359 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime/multipart"
+	"net/url"
+	"path"
+	"server/model/appTypes"
+	"server/model/elasticsearch"
+	"server/model/other"
+	"server/model/request"
+	"server/utils"
+	"server/utils/upload"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
+)
+
+// markdownAbstractLength front matter 中没有简介时，从正文截取的简介长度
+const markdownAbstractLength = 150
+
+// MarkdownReader 读取导入的 Markdown 文件及其引用的本地图片，name 为以 / 分隔的相对路径
+type MarkdownReader func(name string) ([]byte, error)
+
+// ArticleImportFiles 导入上传的 Markdown 文件或 zip 压缩包，同时上传的图片可以被 Markdown 文件引用
+func (articleService *ArticleService) ArticleImportFiles(files []*multipart.FileHeader) (int, []error) {
+	if len(files) == 0 {
+		return 0, []error{errors.New("no file uploaded")}
+	}
+
+	var total int
+	var errs []error
+	looseFiles := make(map[string][]byte)
+	var looseNames []string
+	for _, file := range files {
+		data, err := readFileHeader(file)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", file.Filename, err))
+			continue
+		}
+
+		switch strings.ToLower(path.Ext(file.Filename)) {
+		case ".zip":
+			zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", file.Filename, err))
+				continue
+			}
+			num, zipErrs := articleService.ArticleImportZip(zr)
+			total += num
+			errs = append(errs, zipErrs...)
+		case ".md", ".markdown":
+			looseNames = append(looseNames, path.Base(file.Filename))
+			looseFiles[path.Base(file.Filename)] = data
+		default:
+			looseFiles[path.Base(file.Filename)] = data
+		}
+	}
+
+	num, looseErrs := articleService.ArticleImport(func(name string) ([]byte, error) {
+		data, ok := looseFiles[name]
+		if !ok {
+			return nil, fs.ErrNotExist
+		}
+		return data, nil
+	}, looseNames)
+	return total + num, append(errs, looseErrs...)
+}
+
+// ArticleImportZip 导入 zip 压缩包中的所有 Markdown 文件
+func (articleService *ArticleService) ArticleImportZip(zr *zip.Reader) (int, []error) {
+	var names []string
+	for _, file := range zr.File {
+		if !file.FileInfo().IsDir() && isMarkdownFile(file.Name) {
+			names = append(names, file.Name)
+		}
+	}
+	return articleService.ArticleImport(func(name string) ([]byte, error) {
+		return fs.ReadFile(zr, name)
+	}, names)
+}
+
+// ArticleImport 导入 Markdown 文件，返回成功导入的文章数量以及每个失败文件的错误
+// 文件中引用的本地图片会被上传，并替换为上传后的链接
+func (articleService *ArticleService) ArticleImport(read MarkdownReader, names []string) (int, []error) {
+	var num int
+	var errs []error
+	// 多个文件引用同一张图片时只上传一次
+	uploaded := make(map[string]string)
+	for _, name := range names {
+		if err := articleService.importMarkdown(read, name, uploaded); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+			continue
+		}
+		num++
+	}
+	return num, errs
+}
+
+// ArticleExport 将所有文章导出为带有 front matter 的 Markdown 文件，写入 zip 压缩包
+func (articleService *ArticleService) ArticleExport(w io.Writer) error {
+	zw := zip.NewWriter(w)
+	used := make(map[string]bool)
+	err := articleService.Scan(&types.Query{MatchAll: &types.MatchAllQuery{}}, nil, func(id string, article elasticsearch.Article) error {
+		data, err := utils.FormatFrontMatter(other.ArticleFrontMatter{
+			Title:     article.Title,
+			Category:  article.Category,
+			Tags:      article.Tags,
+			Cover:     article.Cover,
+			Abstract:  article.Abstract,
+			Date:      article.CreatedAt,
+			Status:    frontMatterStatus(article.Status),
+			PublishAt: article.PublishAt,
+		}, article.Content)
+		if err != nil {
+			return err
+		}
+
+		// 优先使用 slug 作为文件名，重复时使用文章 ID
+		name := article.Slug
+		if name == "" || used[name] {
+			name = id
+		}
+		used[name] = true
+
+		f, err := zw.CreateHeader(&zip.FileHeader{
+			Name:     name + ".md",
+			Method:   zip.Deflate,
+			Modified: parseArticleTime(article.UpdatedAt),
+		})
+		if err != nil {
+			return err
+		}
+		_, err = f.Write(data)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	return zw.Close()
+}
+
+// importMarkdown 解析单个 Markdown 文件并创建文章
+func (articleService *ArticleService) importMarkdown(read MarkdownReader, name string, uploaded map[string]string) error {
+	data, err := read(name)
+	if err != nil {
+		return err
+	}
+	var frontMatter other.ArticleFrontMatter
+	content, err := utils.ParseFrontMatter(data, &frontMatter)
+	if err != nil {
+		return err
+	}
+
+	if frontMatter.Title == "" {
+		frontMatter.Title = strings.TrimSuffix(path.Base(name), path.Ext(name))
+	}
+	if frontMatter.Category == "" {
+		return errors.New("missing category in front matter")
+	}
+	if strings.TrimSpace(content) == "" {
+		return errors.New("empty content")
+	}
+	if frontMatter.Tags == nil {
+		frontMatter.Tags = []string{}
+	}
+	if frontMatter.Abstract == "" {
+		frontMatter.Abstract = markdownAbstract(content)
+	}
+	createdAt := ""
+	if frontMatter.Date != "" {
+		t, err := parseFrontMatterDate(frontMatter.Date)
+		if err != nil {
+			return err
+		}
+		createdAt = t.Format("2006-01-02 15:04:05")
+	}
+	status, publishAt, err := parseFrontMatterStatus(frontMatter.Status, frontMatter.PublishAt)
+	if err != nil {
+		return err
+	}
+
+	// 上传封面和正文中引用的本地图片
+	dir := path.Dir(name)
+	if isLocalImage(frontMatter.Cover) {
+		cover, err := uploadMarkdownImage(read, path.Join(dir, frontMatter.Cover), uploaded)
+		if err != nil {
+			return err
+		}
+		frontMatter.Cover = cover
+	}
+	illustrations, err := utils.FindIllustrations(content)
+	if err != nil {
+		return err
+	}
+	for _, illustration := range illustrations {
+		// 图片链接后面可能带有标题，例如 ![alt](a.png "title")
+		ref := strings.Fields(illustration)[0]
+		if !isLocalImage(ref) {
+			continue
+		}
+		imagePath, err := url.PathUnescape(ref)
+		if err != nil {
+			imagePath = ref
+		}
+		imageURL, err := uploadMarkdownImage(read, path.Join(dir, imagePath), uploaded)
+		if err != nil {
+			return err
+		}
+		content = strings.ReplaceAll(content, "]("+ref, "]("+imageURL)
+	}
+
+	return articleService.ArticleCreate(request.ArticleCreate{
+		Cover:     frontMatter.Cover,
+		Title:     frontMatter.Title,
+		Category:  frontMatter.Category,
+		Tags:      frontMatter.Tags,
+		Abstract:  frontMatter.Abstract,
+		Content:   content,
+		Status:    status,
+		PublishAt: publishAt,
+		CreatedAt: createdAt,
+	})
+}
+
+// uploadMarkdownImage 上传 Markdown 文件引用的本地图片，返回上传后的链接
+func uploadMarkdownImage(read MarkdownReader, name string, uploaded map[string]string) (string, error) {
+	name = path.Clean(name)
+	if imageURL, ok := uploaded[name]; ok {
+		return imageURL, nil
+	}
+	data, err := read(name)
+	if err != nil {
+		return "", fmt.Errorf("failed to read image %s: %w", name, err)
+	}
+
+	// 使用完整路径作为文件名，避免不同目录下的同名图片冲突
+	header, err := upload.NewFileHeader(strings.ReplaceAll(name, "/", "_"), data)
+	if err != nil {
+		return "", err
+	}
+	imageURL, err := ServiceGroupApp.ImageService.ImageUpload(header)
+	if err != nil {
+		return "", fmt.Errorf("failed to upload image %s: %w", name, err)
+	}
+	uploaded[name] = imageURL
+	return imageURL, nil
+}
+
+// isLocalImage 判断图片链接是否为相对路径的本地文件
+func isLocalImage(ref string) bool {
+	if ref == "" || strings.HasPrefix(ref, "/") || strings.HasPrefix(ref, "#") {
+		return false
+	}
+	u, err := url.Parse(ref)
+	return err == nil && u.Scheme == "" && u.Host == ""
+}
+
+// isMarkdownFile 判断文件是否为 Markdown 文件，忽略 macOS 生成的元数据文件
+func isMarkdownFile(name string) bool {
+	if strings.HasPrefix(name, "__MACOSX/") || strings.HasPrefix(path.Base(name), ".") {
+		return false
+	}
+	ext := strings.ToLower(path.Ext(name))
+	return ext == ".md" || ext == ".markdown"
+}
+
+// markdownAbstract 从正文中截取简介，去掉标题、图片等 Markdown 标记
+func markdownAbstract(content string) string {
+	var words []string
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "```") || strings.HasPrefix(line, "![") {
+			continue
+		}
+		words = append(words, strings.TrimLeft(line, "#>-*+ "))
+	}
+	abstract := strings.Join(words, " ")
+	if utf8.RuneCountInString(abstract) > markdownAbstractLength {
+		abstract = string([]rune(abstract)[:markdownAbstractLength]) + "..."
+	}
+	return abstract
+}
+
+// parseFrontMatterDate 解析 front matter 中的日期，支持常见的几种格式
+func parseFrontMatterDate(value string) (time.Time, error) {
+	for _, layout := range []string{"2006-01-02 15:04:05", time.RFC3339, "2006-01-02T15:04:05", "2006-01-02 15:04", "2006-01-02"} {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return t.Local(), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q", value)
+}
+
+// frontMatterStatuses front matter 中的文章状态
+var frontMatterStatuses = map[appTypes.ArticleStatus]string{
+	appTypes.Published: "published",
+	appTypes.Draft:     "draft",
+	appTypes.Scheduled: "scheduled",
+	appTypes.Archived:  "archived",
+}
+
+// frontMatterStatus 返回文章状态在 front matter 中的写法，未设置状态的旧文章视为已发布
+func frontMatterStatus(status appTypes.ArticleStatus) string {
+	if value, ok := frontMatterStatuses[status]; ok {
+		return value
+	}
+	return frontMatterStatuses[appTypes.Published]
+}
+
+// parseFrontMatterStatus 解析 front matter 中的文章状态和发布时间，未设置状态时与导出时一致视为已发布，
+// 定时发布时间已经过去的文章视为已发布，其他状态的文章保留 front matter 中的发布时间
+func parseFrontMatterStatus(value, publishAt string) (appTypes.ArticleStatus, string, error) {
+	status := appTypes.Published
+	if value != "" {
+		found := false
+		for s, name := range frontMatterStatuses {
+			if strings.EqualFold(value, name) {
+				status, found = s, true
+				break
+			}
+		}
+		if !found {
+			return 0, "", fmt.Errorf("invalid status %q", value)
+		}
+	}
+
+	if publishAt == "" {
+		if status == appTypes.Scheduled {
+			return 0, "", errors.New("publish_at is required for a scheduled article")
+		}
+		return status, "", nil
+	}
+	t, err := parseFrontMatterDate(publishAt)
+	if err != nil {
+		return 0, "", fmt.Errorf("invalid publish_at: %w", err)
+	}
+	if status == appTypes.Scheduled && !t.After(time.Now()) {
+		status = appTypes.Published
+	}
+	return status, t.Format("2006-01-02 15:04:05"), nil
+}
+
+// readFileHeader 读取上传文件的全部内容
+func readFileHeader(file *multipart.FileHeader) ([]byte, error) {
+	f, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return io.ReadAll(f)
+}
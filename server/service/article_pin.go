@@ -0,0 +1,86 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"server/global"
+	"server/model/elasticsearch"
+	"server/model/request"
+	"server/model/response"
+
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types/enums/sortorder"
+)
+
+// featuredSize 默认返回的精选文章数量
+const featuredSize = 5
+
+// ArticlePin 置顶或取消置顶文章
+func (articleService *ArticleService) ArticlePin(req request.ArticlePin) error {
+	if _, err := articleService.Get(req.ID); err != nil {
+		return err
+	}
+	// 取消置顶时清空置顶顺序，保证未置顶的文章排序时不受影响
+	pinOrder := req.PinOrder
+	if !req.Pinned {
+		pinOrder = 0
+	}
+	articleToUpdate := struct {
+		Pinned   bool `json:"pinned"`
+		PinOrder int  `json:"pin_order"`
+	}{
+		Pinned:   req.Pinned,
+		PinOrder: pinOrder,
+	}
+	return articleService.Update(req.ID, articleToUpdate)
+}
+
+// ArticleFeature 设置或取消精选文章
+func (articleService *ArticleService) ArticleFeature(req request.ArticleFeature) error {
+	if _, err := articleService.Get(req.ID); err != nil {
+		return err
+	}
+	articleToUpdate := struct {
+		Featured bool `json:"featured"`
+	}{
+		Featured: req.Featured,
+	}
+	return articleService.Update(req.ID, articleToUpdate)
+}
+
+// ArticleFeatured 获取已发布的精选文章，置顶的文章优先，其余按创建时间倒序
+func (articleService *ArticleService) ArticleFeatured(req request.ArticleFeatured) ([]response.ArticleHit, error) {
+	size := req.Size
+	if size <= 0 {
+		size = featuredSize
+	}
+
+	sort := append(pinnedSort(), types.SortOptions{SortOptions: map[string]types.FieldSort{"created_at": {Order: &sortorder.Desc}}})
+	res, err := global.ESClient.Search().
+		Index(elasticsearch.ArticleIndex()).
+		Query(&types.Query{
+			Bool: &types.BoolQuery{
+				Filter: []types.Query{
+					publishedQuery(),
+					{Term: map[string]types.TermQuery{"featured": {Value: true}}},
+				},
+			},
+		}).
+		Sort(sort...).
+		SourceExcludes_("content", "content_html", "toc").
+		Size(size).
+		Do(context.TODO())
+	if err != nil {
+		return nil, err
+	}
+
+	hits := []response.ArticleHit{}
+	for _, hit := range res.Hits.Hits {
+		var article elasticsearch.Article
+		if err := json.Unmarshal(hit.Source_, &article); err != nil {
+			return nil, err
+		}
+		hits = append(hits, response.ArticleHit{Id_: *hit.Id_, Source_: article})
+	}
+	return hits, nil
+}
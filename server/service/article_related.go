@@ -0,0 +1,107 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"server/global"
+	"server/model/appTypes"
+	"server/model/elasticsearch"
+	"server/model/response"
+	"time"
+
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
+)
+
+// relatedSize 相关文章推荐的数量
+const relatedSize = 5
+
+// relatedCacheKey 相关文章在 Redis 中缓存使用的哈希表，字段为文章 ID
+const relatedCacheKey = "article_related"
+
+// relatedCacheTTL 相关文章缓存的有效期
+const relatedCacheTTL = 24 * time.Hour
+
+// ArticleRelated 获取与指定文章相关的文章，基于标题、简介和内容的相似度，同一标签和类别的文章会被优先推荐
+func (articleService *ArticleService) ArticleRelated(id string) ([]response.ArticleHit, error) {
+	if data, err := global.Redis.HGet(relatedCacheKey, id).Result(); err == nil {
+		var hits []response.ArticleHit
+		if err := json.Unmarshal([]byte(data), &hits); err == nil {
+			return hits, nil
+		}
+	}
+
+	article, err := articleService.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	if article.Status != appTypes.Published {
+		return nil, errors.New("document not found")
+	}
+
+	index := elasticsearch.ArticleIndex()
+	minTermFreq := 1
+	minDocFreq := 1
+	maxQueryTerms := 50
+	tagBoost := float32(2)
+	categoryBoost := float32(1.5)
+	boolQuery := &types.BoolQuery{
+		Must: []types.Query{
+			{
+				MoreLikeThis: &types.MoreLikeThisQuery{
+					Fields:        []string{"title", "abstract", "content"},
+					Like:          []types.Like{types.LikeDocument{Index_: &index, Id_: &id}},
+					MinTermFreq:   &minTermFreq,
+					MinDocFreq:    &minDocFreq,
+					MaxQueryTerms: &maxQueryTerms,
+				},
+			},
+		},
+		Filter:  []types.Query{publishedQuery()},
+		MustNot: []types.Query{{Ids: &types.IdsQuery{Values: []string{id}}}},
+	}
+	if len(article.Tags) > 0 {
+		tags := make([]types.FieldValue, 0, len(article.Tags))
+		for _, tag := range article.Tags {
+			tags = append(tags, tag)
+		}
+		boolQuery.Should = append(boolQuery.Should, types.Query{
+			Terms: &types.TermsQuery{TermsQuery: map[string]types.TermsQueryField{"tags": tags}, Boost: &tagBoost},
+		})
+	}
+	if article.Category != "" {
+		boolQuery.Should = append(boolQuery.Should, types.Query{
+			Term: map[string]types.TermQuery{"category": {Value: article.Category, Boost: &categoryBoost}},
+		})
+	}
+
+	res, err := global.ESClient.Search().
+		Index(index).
+		Query(&types.Query{Bool: boolQuery}).
+		SourceExcludes_("content", "content_html", "toc").
+		Size(relatedSize).
+		Do(context.TODO())
+	if err != nil {
+		return nil, err
+	}
+
+	hits := []response.ArticleHit{}
+	for _, hit := range res.Hits.Hits {
+		var related elasticsearch.Article
+		if err := json.Unmarshal(hit.Source_, &related); err != nil {
+			return nil, err
+		}
+		hits = append(hits, response.ArticleHit{Id_: *hit.Id_, Source_: related})
+	}
+
+	if data, err := json.Marshal(hits); err == nil {
+		global.Redis.HSet(relatedCacheKey, id, data)
+		global.Redis.Expire(relatedCacheKey, relatedCacheTTL)
+	}
+	return hits, nil
+}
+
+// ClearRelatedCache 清除相关文章缓存，任意文章变化都可能影响其他文章的推荐结果
+func (articleService *ArticleService) ClearRelatedCache() error {
+	return global.Redis.Del(relatedCacheKey).Err()
+}
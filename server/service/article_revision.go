@@ -0,0 +1,80 @@
+package service
+
+import (
+	"errors"
+	"server/global"
+	"server/model/database"
+	"server/model/other"
+	"server/model/request"
+	"server/model/response"
+	"server/utils"
+)
+
+func (articleService *ArticleService) ArticleRevisionList(info request.ArticleRevisionList) (interface{}, int64, error) {
+	db := global.DB.Where("article_id = ?", info.ArticleID)
+	option := other.MySQLOption{
+		PageInfo: info.PageInfo,
+		Where:    db,
+		Preload:  []string{"ReplacedBy"},
+	}
+
+	return utils.MySQLPagination(&database.ArticleRevision{}, option)
+}
+
+func (articleService *ArticleService) ArticleRevisionDiff(req request.ArticleRevisionDiff) (response.ArticleRevisionDiff, error) {
+	var from database.ArticleRevision
+	if err := global.DB.Take(&from, req.From).Error; err != nil {
+		return response.ArticleRevisionDiff{}, err
+	}
+
+	var to database.ArticleRevision
+	if req.To != 0 {
+		if err := global.DB.Take(&to, req.To).Error; err != nil {
+			return response.ArticleRevisionDiff{}, err
+		}
+		if to.ArticleID != from.ArticleID {
+			return response.ArticleRevisionDiff{}, errors.New("the revisions do not belong to the same article")
+		}
+	} else {
+		// 未指定目标版本时，与文章当前版本比较
+		article, err := articleService.Get(from.ArticleID)
+		if err != nil {
+			return response.ArticleRevisionDiff{}, err
+		}
+		to = database.ArticleRevision{
+			ArticleID: from.ArticleID,
+			Title:     article.Title,
+			Abstract:  article.Abstract,
+			Content:   article.Content,
+			Tags:      article.Tags,
+			Category:  article.Category,
+			Cover:     article.Cover,
+			VersionAt: article.UpdatedAt,
+		}
+	}
+
+	return response.ArticleRevisionDiff{
+		From: from,
+		To:   to,
+		Diff: utils.DiffLines(from.Content, to.Content),
+	}, nil
+}
+
+func (articleService *ArticleService) ArticleRevisionRestore(req request.ArticleRevisionRestore) error {
+	var revision database.ArticleRevision
+	if err := global.DB.Take(&revision, req.ID).Error; err != nil {
+		return err
+	}
+
+	// 通过正常的更新流程回滚，保证类别、标签计数以及图片类别的一致性，当前版本也会被保存为历史版本
+	return articleService.ArticleUpdate(request.ArticleUpdate{
+		EditorID: req.EditorID,
+		ID:       revision.ArticleID,
+		Cover:    revision.Cover,
+		Title:    revision.Title,
+		Category: revision.Category,
+		Tags:     revision.Tags,
+		Abstract: revision.Abstract,
+		Content:  revision.Content,
+	})
+}
@@ -0,0 +1,130 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"server/global"
+	"server/model/database"
+	"server/model/elasticsearch"
+	"server/model/response"
+	"server/utils"
+	"strconv"
+
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
+)
+
+func (articleService *ArticleService) ArticleInfoBySlug(slug string) (response.ArticleHit, string, error) {
+	// 优先查找当前使用该 slug 的文章
+	req := &types.Query{
+		Bool: &types.BoolQuery{
+			Filter: []types.Query{
+				{Term: map[string]types.TermQuery{"slug": {Value: slug}}},
+				publishedQuery(),
+			},
+		},
+	}
+	res, err := global.ESClient.Search().Index(elasticsearch.ArticleIndex()).Query(req).Size(1).Do(context.TODO())
+	if err != nil {
+		return response.ArticleHit{}, "", err
+	}
+	if len(res.Hits.Hits) > 0 {
+		id := *res.Hits.Hits[0].Id_
+		article, err := articleService.ArticleInfoByID(id)
+		if err != nil {
+			return response.ArticleHit{}, "", err
+		}
+		return response.ArticleHit{Id_: id, Source_: article.Article, Series: article.Series}, "", nil
+	}
+
+	// 否则查找历史 slug，返回文章当前的 slug 用于重定向
+	var history database.ArticleSlug
+	if err := global.DB.Where("slug = ?", slug).First(&history).Error; err != nil {
+		return response.ArticleHit{}, "", err
+	}
+	article, err := articleService.ArticleInfoByID(history.ArticleID)
+	if err != nil {
+		return response.ArticleHit{}, "", err
+	}
+	if article.Slug == "" {
+		return response.ArticleHit{}, "", errors.New("document not found")
+	}
+	return response.ArticleHit{}, article.Slug, nil
+}
+
+// GenerateUniqueSlug 根据标题生成唯一的 slug，与其他文章冲突时追加数字后缀
+func (articleService *ArticleService) GenerateUniqueSlug(title, articleID string) (string, error) {
+	base := utils.GenerateSlug(title)
+	slug := base
+	for i := 2; ; i++ {
+		exists, err := articleService.SlugExists(slug, articleID)
+		if err != nil {
+			return "", err
+		}
+		if !exists {
+			return slug, nil
+		}
+		slug = base + "-" + strconv.Itoa(i)
+	}
+}
+
+// SlugExists 检查 slug 是否已被其他文章使用，包括其他文章的历史 slug
+func (articleService *ArticleService) SlugExists(slug, articleID string) (bool, error) {
+	boolQuery := &types.BoolQuery{
+		Filter: []types.Query{
+			{Term: map[string]types.TermQuery{"slug": {Value: slug}}},
+		},
+	}
+	if articleID != "" {
+		boolQuery.MustNot = []types.Query{{Ids: &types.IdsQuery{Values: []string{articleID}}}}
+	}
+	res, err := global.ESClient.Search().Index(elasticsearch.ArticleIndex()).Query(&types.Query{Bool: boolQuery}).Size(0).Do(context.TODO())
+	if err != nil {
+		return false, err
+	}
+	if res.Hits.Total.Value > 0 {
+		return true, nil
+	}
+
+	var count int64
+	db := global.DB.Model(&database.ArticleSlug{}).Where("slug = ?", slug)
+	if articleID != "" {
+		db = db.Where("article_id <> ?", articleID)
+	}
+	if err := db.Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// ArticleBackfillSlugs 为没有 slug 的文章生成 slug，返回处理的文章数量，索引不存在时不做任何操作
+func (articleService *ArticleService) ArticleBackfillSlugs() (int, error) {
+	exists, err := ServiceGroupApp.EsService.IndexExists(elasticsearch.ArticleIndex())
+	if err != nil || !exists {
+		return 0, err
+	}
+
+	// 先收集需要处理的文章，避免遍历过程中修改文档
+	titles := make(map[string]string)
+	err = articleService.Scan(&types.Query{MatchAll: &types.MatchAllQuery{}}, []string{"title", "slug"}, func(id string, article elasticsearch.Article) error {
+		if article.Slug == "" {
+			titles[id] = article.Title
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	for id, title := range titles {
+		slug, err := articleService.GenerateUniqueSlug(title, id)
+		if err != nil {
+			return 0, err
+		}
+		if err := articleService.Update(id, struct {
+			Slug string `json:"slug"`
+		}{slug}); err != nil {
+			return 0, err
+		}
+	}
+	return len(titles), nil
+}
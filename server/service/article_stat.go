@@ -3,24 +3,36 @@ package service
 import (
 	"server/global"
 	"strconv"
+	"time"
+
+	"github.com/go-redis/redis"
 )
 
+// articleViewWindow 同一访客在该时间内重复访问同一篇文章只计一次浏览量
+const articleViewWindow = 30 * time.Minute
+
 func (articleService *ArticleService) NewArticleView() CountDB {
 	return CountDB{
 		Index: "article_views",
 	}
 }
 
+// ArticleViewed 记录一次文章浏览，viewer 为访客标识，窗口期内的重复访问不计入浏览量
+func (articleService *ArticleService) ArticleViewed(id, viewer string) error {
+	first, err := global.Redis.SetNX("article_view:"+id+":"+viewer, 1, articleViewWindow).Result()
+	if err != nil || !first {
+		return err
+	}
+	return articleService.NewArticleView().Set(id)
+}
+
 type CountDB struct {
 	Index string
 }
 
 // Set 在原有基础上加一
 func (c CountDB) Set(id string) error {
-	num, _ := global.Redis.HGet(c.Index, id).Int()
-	num++
-	err := global.Redis.HSet(c.Index, id, num).Err()
-	return err
+	return global.Redis.HIncrBy(c.Index, id, 1).Err()
 }
 
 // GetInfo 取出数据
@@ -34,7 +46,16 @@ func (c CountDB) GetInfo() map[string]int {
 	return Info
 }
 
-// Clear 清除数据
-func (c CountDB) Clear() {
-	global.Redis.Del(c.Index)
+// subtractScript 扣减计数，扣减后不大于 0 时删除该字段
+var subtractScript = redis.NewScript(`
+local num = redis.call('HINCRBY', KEYS[1], ARGV[1], -tonumber(ARGV[2]))
+if num <= 0 then
+	redis.call('HDEL', KEYS[1], ARGV[1])
+end
+return num
+`)
+
+// Subtract 扣减已同步的数据，同步期间新增的计数会被保留
+func (c CountDB) Subtract(id string, num int) error {
+	return subtractScript.Run(&global.Redis, []string{c.Index}, id, num).Err()
 }
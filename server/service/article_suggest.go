@@ -0,0 +1,99 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"server/global"
+	"server/model/appTypes"
+	"server/model/elasticsearch"
+	"server/model/request"
+	"server/model/response"
+
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
+)
+
+// suggestSize 默认返回的搜索建议数量
+const suggestSize = 5
+
+// ArticleSuggest 根据输入的前缀返回搜索建议，只包含已发布的文章，允许少量拼写错误
+func (articleService *ArticleService) ArticleSuggest(req request.ArticleSuggest) ([]response.ArticleSuggest, error) {
+	size := req.Size
+	if size <= 0 {
+		size = suggestSize
+	}
+	skipDuplicates := true
+
+	res, err := global.ESClient.Search().
+		Index(elasticsearch.ArticleIndex()).
+		Suggest(&types.Suggester{
+			Suggesters: map[string]types.FieldSuggester{
+				"article": {
+					Prefix: &req.Prefix,
+					Completion: &types.CompletionSuggester{
+						Field:          "suggest",
+						Size:           &size,
+						SkipDuplicates: &skipDuplicates,
+						Fuzzy:          &types.SuggestFuzziness{Fuzziness: "AUTO", PrefixLength: &fuzzyPrefixLength},
+						Contexts: map[string][]types.CompletionContext{
+							"status": {{Context: appTypes.Published.String()}},
+						},
+					},
+				},
+			},
+		}).
+		SourceIncludes_("title", "slug").
+		TypedKeys(true).
+		Do(context.TODO())
+	if err != nil {
+		return nil, err
+	}
+
+	suggestions := []response.ArticleSuggest{}
+	for _, suggest := range res.Suggest["article"] {
+		completion, ok := suggest.(*types.CompletionSuggest)
+		if !ok {
+			continue
+		}
+		for _, option := range completion.Options {
+			var article elasticsearch.Article
+			if err := json.Unmarshal(option.Source_, &article); err != nil {
+				return nil, err
+			}
+			suggestion := response.ArticleSuggest{Text: option.Text, Title: article.Title, Slug: article.Slug}
+			if option.Id_ != nil {
+				suggestion.ID = *option.Id_
+			}
+			suggestions = append(suggestions, suggestion)
+		}
+	}
+	return suggestions, nil
+}
+
+// ArticleBackfillSuggest 为引入搜索建议之前创建的文章生成候选词，返回处理的文章数量，索引不存在时不做任何操作
+func (articleService *ArticleService) ArticleBackfillSuggest() (int, error) {
+	exists, err := ServiceGroupApp.EsService.IndexExists(elasticsearch.ArticleIndex())
+	if err != nil || !exists {
+		return 0, err
+	}
+
+	// 先收集需要处理的文章，避免遍历过程中修改文档
+	suggests := make(map[string][]string)
+	err = articleService.Scan(&types.Query{MatchAll: &types.MatchAllQuery{}}, []string{"title", "tags", "suggest"}, func(id string, article elasticsearch.Article) error {
+		if len(article.Suggest) == 0 {
+			suggests[id] = articleSuggest(article.Title, article.Tags)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	for id, suggest := range suggests {
+		if err := articleService.Update(id, struct {
+			Suggest []string `json:"suggest"`
+		}{suggest}); err != nil {
+			return 0, err
+		}
+	}
+	return len(suggests), nil
+}
@@ -0,0 +1,170 @@
+package service
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"server/global"
+	"server/model/database"
+	"server/model/response"
+	"server/utils/upload"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// backupNameLayout 备份名称的时间格式
+const backupNameLayout = "20060102150405"
+
+// backupExt 备份文件的扩展名
+const backupExt = ".tar.gz"
+
+// backupMutex 避免同时运行多个备份
+var backupMutex sync.Mutex
+
+type BackupService struct {
+}
+
+// Tables 返回所有数据表的表名
+func (backupService *BackupService) Tables() ([]string, error) {
+	var tables []string
+	for _, model := range database.Models() {
+		stmt := &gorm.Statement{DB: global.DB}
+		if err := stmt.Parse(model); err != nil {
+			return nil, err
+		}
+		tables = append(tables, stmt.Schema.Table)
+	}
+	return tables, nil
+}
+
+// BackupCreate 生成与 --backup 相同格式的备份包并保存到备份存储中，返回备份名称
+// 备份名称由备份时间和随机后缀组成，避免对象名称被猜到
+func (backupService *BackupService) BackupCreate() (string, error) {
+	if !backupMutex.TryLock() {
+		return "", errors.New("a backup is already running")
+	}
+	defer backupMutex.Unlock()
+
+	backupStorage, err := upload.NewBackupStorage()
+	if err != nil {
+		return "", err
+	}
+
+	suffix := make([]byte, 8)
+	if _, err := rand.Read(suffix); err != nil {
+		return "", err
+	}
+	name := time.Now().Format(backupNameLayout) + "_" + hex.EncodeToString(suffix)
+
+	// 先写入本地的临时目录，完成后再保存到备份存储
+	staging := filepath.Join(global.Config.Backup.Dir(), "."+name)
+	if err := os.MkdirAll(staging, os.ModePerm); err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(staging)
+
+	archivePath := filepath.Join(staging, name+backupExt)
+	if _, err := backupService.BackupArchive(archivePath); err != nil {
+		return "", err
+	}
+	if err := backupStorage.SaveBackup(name+backupExt, archivePath); err != nil {
+		return "", err
+	}
+	return name, nil
+}
+
+// BackupList 列出备份存储中的所有备份，按时间倒序排列
+func (backupService *BackupService) BackupList() ([]response.BackupInfo, error) {
+	backupStorage, err := upload.NewBackupStorage()
+	if err != nil {
+		return nil, err
+	}
+	objects, err := backupStorage.ListBackups()
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]response.BackupInfo, 0, len(objects))
+	for _, object := range objects {
+		name, ok := strings.CutSuffix(object.Key, backupExt)
+		if !ok || strings.Contains(name, "/") {
+			continue
+		}
+		createdAt, err := parseBackupName(name)
+		if err != nil {
+			continue
+		}
+		list = append(list, response.BackupInfo{
+			Name:      name,
+			CreatedAt: createdAt.Format("2006-01-02 15:04:05"),
+			Size:      object.Size,
+		})
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Name > list[j].Name
+	})
+	return list, nil
+}
+
+// BackupPrune 按照保留策略删除过期的备份，保留最近若干天每天最新的备份和最近若干周每周最新的备份，返回删除的备份数量
+func (backupService *BackupService) BackupPrune() (int, error) {
+	daily, weekly := global.Config.Backup.Daily, global.Config.Backup.Weekly
+	// 未配置保留策略时保留所有备份
+	if daily <= 0 && weekly <= 0 {
+		return 0, nil
+	}
+
+	backupStorage, err := upload.NewBackupStorage()
+	if err != nil {
+		return 0, err
+	}
+	backups, err := backupService.BackupList()
+	if err != nil {
+		return 0, err
+	}
+
+	// backups 按时间倒序排列，每天和每周最先遇到的即为最新的备份
+	days := make(map[string]bool)
+	weeks := make(map[string]bool)
+	deleted := 0
+	for _, backup := range backups {
+		createdAt, err := parseBackupName(backup.Name)
+		if err != nil {
+			continue
+		}
+		keep := false
+		day := createdAt.Format("2006-01-02")
+		if !days[day] && len(days) < daily {
+			days[day] = true
+			keep = true
+		}
+		year, week := createdAt.ISOWeek()
+		weekKey := fmt.Sprintf("%d-%d", year, week)
+		if !weeks[weekKey] && len(weeks) < weekly {
+			weeks[weekKey] = true
+			keep = true
+		}
+		if keep {
+			continue
+		}
+
+		if err := backupStorage.DeleteBackup(backup.Name + backupExt); err != nil {
+			return deleted, err
+		}
+		deleted++
+	}
+	return deleted, nil
+}
+
+// parseBackupName 从备份名称中解析出备份时间
+func parseBackupName(name string) (time.Time, error) {
+	timestamp, _, _ := strings.Cut(name, "_")
+	return time.ParseInLocation(backupNameLayout, timestamp, time.Local)
+}
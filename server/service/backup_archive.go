@@ -0,0 +1,173 @@
+package service
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"server/global"
+	"server/model/elasticsearch"
+	"server/model/other"
+	"server/utils"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// BackupVersion 当前的备份格式版本，格式发生不兼容的变化时递增
+const BackupVersion = 1
+
+// 备份包中的文件
+const (
+	BackupManifestFile = "manifest.json"
+	BackupSQLFile      = "mysql.sql"
+	BackupESFile       = "elasticsearch.ndjson"
+	BackupConfigFile   = "config.yaml"
+	BackupUploadsDir   = "uploads"
+)
+
+// BackupArchive 将 MySQL 数据、ES 文章、本地上传的文件以及去除敏感信息的配置打包为一个带清单和校验值的备份文件
+func (backupService *BackupService) BackupArchive(archivePath string) (other.BackupManifest, error) {
+	manifest := other.BackupManifest{
+		Version:   BackupVersion,
+		CreatedAt: time.Now().Format("2006-01-02 15:04:05"),
+	}
+
+	dir, err := os.MkdirTemp("", "blog-backup-*")
+	if err != nil {
+		return manifest, err
+	}
+	defer os.RemoveAll(dir)
+
+	// 导出 MySQL 数据
+	manifest.Tables, err = backupService.Tables()
+	if err != nil {
+		return manifest, err
+	}
+	sqlDB, err := global.DB.DB()
+	if err != nil {
+		return manifest, err
+	}
+	err = utils.WriteFile(filepath.Join(dir, BackupSQLFile), func(w io.Writer) error {
+		return utils.DumpSQL(sqlDB, w, manifest.Tables)
+	})
+	if err != nil {
+		return manifest, err
+	}
+	global.Log.Info(fmt.Sprintf("Backed up %d MySQL tables", len(manifest.Tables)))
+
+	// 导出 ES 文章
+	err = utils.WriteFile(filepath.Join(dir, BackupESFile), func(w io.Writer) error {
+		return ServiceGroupApp.EsService.ScanDocuments(elasticsearch.ArticleIndex(), &other.EsPosition{}, func(batch []other.Data, _ other.EsPosition) error {
+			manifest.Documents += len(batch)
+			return utils.WriteNDJSON(w, batch)
+		})
+	})
+	if err != nil {
+		return manifest, err
+	}
+	global.Log.Info(fmt.Sprintf("Backed up %d ES documents", manifest.Documents))
+
+	// 导出去除敏感信息的配置
+	configData, err := yaml.Marshal(global.Config.Sanitized())
+	if err != nil {
+		return manifest, err
+	}
+	if err := os.WriteFile(filepath.Join(dir, BackupConfigFile), configData, 0644); err != nil {
+		return manifest, err
+	}
+
+	// 打包所有文件，清单最后写入
+	archive, err := os.Create(archivePath)
+	if err != nil {
+		return manifest, err
+	}
+	defer archive.Close()
+	gz := gzip.NewWriter(archive)
+	tw := tar.NewWriter(gz)
+
+	for _, name := range []string{BackupSQLFile, BackupESFile, BackupConfigFile} {
+		file, err := addBackupFile(tw, name, filepath.Join(dir, name))
+		if err != nil {
+			return manifest, err
+		}
+		manifest.Files = append(manifest.Files, file)
+	}
+
+	uploads := 0
+	err = filepath.WalkDir(global.Config.Upload.Path, func(p string, d fs.DirEntry, err error) error {
+		if errors.Is(err, fs.ErrNotExist) && p == global.Config.Upload.Path {
+			return filepath.SkipDir
+		}
+		if err != nil || !d.Type().IsRegular() {
+			return err
+		}
+		rel, err := filepath.Rel(global.Config.Upload.Path, p)
+		if err != nil {
+			return err
+		}
+		file, err := addBackupFile(tw, path.Join(BackupUploadsDir, filepath.ToSlash(rel)), p)
+		if err != nil {
+			return err
+		}
+		manifest.Files = append(manifest.Files, file)
+		uploads++
+		return nil
+	})
+	if err != nil {
+		return manifest, err
+	}
+	global.Log.Info(fmt.Sprintf("Backed up %d uploaded files", uploads))
+
+	manifestData, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return manifest, err
+	}
+	if err := tw.WriteHeader(&tar.Header{Name: BackupManifestFile, Mode: 0644, Size: int64(len(manifestData)), ModTime: time.Now()}); err != nil {
+		return manifest, err
+	}
+	if _, err := tw.Write(manifestData); err != nil {
+		return manifest, err
+	}
+
+	if err := tw.Close(); err != nil {
+		return manifest, err
+	}
+	if err := gz.Close(); err != nil {
+		return manifest, err
+	}
+	if err := archive.Sync(); err != nil {
+		return manifest, err
+	}
+	return manifest, archive.Close()
+}
+
+// addBackupFile 将本地文件写入备份包，同时计算其校验值
+func addBackupFile(tw *tar.Writer, name, localPath string) (other.BackupFile, error) {
+	file, err := os.Open(localPath)
+	if err != nil {
+		return other.BackupFile{}, err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return other.BackupFile{}, err
+	}
+	if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: info.Size(), ModTime: info.ModTime()}); err != nil {
+		return other.BackupFile{}, err
+	}
+	hash := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(tw, hash), file); err != nil {
+		return other.BackupFile{}, err
+	}
+	return other.BackupFile{Name: name, Size: info.Size(), SHA256: hex.EncodeToString(hash.Sum(nil))}, nil
+}
@@ -1,32 +1,107 @@
 package service
 
 import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"server/global"
+	"server/model/appTypes"
 	"server/utils"
+	"strings"
 	"time"
 
-	"github.com/gin-contrib/sessions"
-	"github.com/gin-gonic/gin"
+	"github.com/go-redis/redis"
 )
 
 type BaseService struct {
 }
 
-func (baseService *BaseService) SendEmailVerificationCode(c *gin.Context, to string) error {
-	verificationCode := utils.GenerateVerificationCode(6)
-	expireTime := time.Now().Add(5 * time.Minute).Unix()
+const (
+	emailCodeTTL           = 5 * time.Minute // 邮箱验证码的有效期
+	emailCodeMaxAttempts   = 5               // 邮箱验证码允许的最大错误次数，超过后验证码作废
+	emailCodeCooldown      = time.Minute     // 同一邮箱两次发送之间的最短间隔
+	emailCodeLimitWindow   = time.Hour       // 发送次数的统计周期
+	emailCodeLimitPerEmail = 10              // 同一邮箱在统计周期内的最大发送次数
+	emailCodeLimitPerIP    = 20              // 同一 IP 在统计周期内的最大发送次数
+)
+
+var (
+	ErrEmailCodeTooFrequent = errors.New("verification codes are sent too frequently, please try again later")
+	ErrEmailCodeInvalid     = errors.New("invalid verification code")
+	ErrEmailCodeExpired     = errors.New("the verification code has expired, please resend it")
+)
+
+// emailCodeKey 返回邮箱验证码在 Redis 中的键，按用途和邮箱区分
+func emailCodeKey(email string, purpose appTypes.EmailPurpose) string {
+	return "email_code:" + string(purpose) + ":" + email
+}
+
+// emailCodeHash 使用服务端密钥计算验证码的 HMAC，Redis 中只保存 HMAC，
+// 验证码只有 6 位，不加密钥的哈希值可以被轻易穷举
+func emailCodeHash(email string, purpose appTypes.EmailPurpose, code string) string {
+	mac := hmac.New(sha256.New, global.Config.System.VerificationSecret())
+	mac.Write([]byte(string(purpose) + "|" + email + "|" + code))
+	return hex.EncodeToString(mac.Sum(nil))
+}
 
-	// 将验证码、验证邮箱、过期时间存入会话中
-	session := sessions.Default(c)
-	session.Set("verification_code", verificationCode)
-	session.Set("email", to)
-	session.Set("expire_time", expireTime)
-	_ = session.Save()
+// emailCodeAllow 检查并记录一次发送，超过发送频率限制时返回 false
+func emailCodeAllow(email, ip string) (bool, error) {
+	ok, err := global.Redis.SetNX("email_code_cooldown:"+email, 1, emailCodeCooldown).Result()
+	if err != nil || !ok {
+		return false, err
+	}
+	limits := map[string]int64{
+		"email_code_limit:email:" + email: emailCodeLimitPerEmail,
+		"email_code_limit:ip:" + ip:       emailCodeLimitPerIP,
+	}
+	for key, limit := range limits {
+		count, err := global.Redis.Incr(key).Result()
+		if err != nil {
+			return false, err
+		}
+		if count == 1 {
+			if err := global.Redis.Expire(key, emailCodeLimitWindow).Err(); err != nil {
+				return false, err
+			}
+		}
+		if count > limit {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// SendEmailVerificationCode 生成邮箱验证码并发送，验证码以哈希形式保存在 Redis 中，同一邮箱和 IP 的发送频率受到限制
+func (baseService *BaseService) SendEmailVerificationCode(to string, purpose appTypes.EmailPurpose, ip string) error {
+	to = strings.ToLower(to)
+	ok, err := emailCodeAllow(to, ip)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrEmailCodeTooFrequent
+	}
+
+	verificationCode := utils.GenerateVerificationCode(6)
+	key := emailCodeKey(to, purpose)
+	_, err = global.Redis.TxPipelined(func(pipe redis.Pipeliner) error {
+		pipe.Del(key)
+		pipe.HMSet(key, map[string]interface{}{
+			"hash":     emailCodeHash(to, purpose, verificationCode),
+			"attempts": 0,
+		})
+		pipe.Expire(key, emailCodeTTL)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
 
 	subject := "您的邮箱验证码"
 	body := `亲爱的用户[` + to + `]，<br/>
 <br/>
-感谢您注册` + global.Config.Website.Name + `的个人博客！为了确保您的邮箱安全，请使用以下验证码进行验证：<br/>
+您正在` + global.Config.Website.Name + `的个人博客进行` + purpose.String() + `操作，为了确保您的邮箱安全，请使用以下验证码进行验证：<br/>
 <br/>
 验证码：[<font color="blue"><u>` + verificationCode + `</u></font>]<br/>
 该验证码在 5 分钟内有效，请尽快使用。<br/>
@@ -40,7 +115,46 @@ func (baseService *BaseService) SendEmailVerificationCode(c *gin.Context, to str
 		global.Config.Website.Title + `<br/>
 <br/>`
 
-	_ = utils.Email(to, subject, body)
-
+	if err := utils.Email(to, subject, body); err != nil {
+		// 发送失败时作废验证码，并允许立即重新发送
+		global.Redis.Del(key, "email_code_cooldown:"+to)
+		return err
+	}
 	return nil
 }
+
+// verifyEmailCodeScript 校验验证码，成功后删除验证码，错误次数达到上限时同样删除验证码
+// 返回 1 表示校验成功，0 表示验证码错误，-1 表示验证码不存在或已作废
+var verifyEmailCodeScript = redis.NewScript(`
+local hash = redis.call('HGET', KEYS[1], 'hash')
+if not hash then
+	return -1
+end
+if hash == ARGV[1] then
+	redis.call('DEL', KEYS[1])
+	return 1
+end
+local attempts = redis.call('HINCRBY', KEYS[1], 'attempts', 1)
+if attempts >= tonumber(ARGV[2]) then
+	redis.call('DEL', KEYS[1])
+end
+return 0
+`)
+
+// VerifyEmailCode 校验邮箱验证码，验证码只能使用一次
+func (baseService *BaseService) VerifyEmailCode(email string, purpose appTypes.EmailPurpose, code string) error {
+	email = strings.ToLower(email)
+	result, err := verifyEmailCodeScript.Run(&global.Redis, []string{emailCodeKey(email, purpose)},
+		emailCodeHash(email, purpose, code), emailCodeMaxAttempts).Int()
+	if err != nil {
+		return err
+	}
+	switch result {
+	case 1:
+		return nil
+	case 0:
+		return ErrEmailCodeInvalid
+	default:
+		return ErrEmailCodeExpired
+	}
+}
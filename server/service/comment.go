@@ -49,12 +49,18 @@ func (commentService *CommentService) CommentNew() ([]database.Comment, error) {
 }
 
 func (commentService *CommentService) CommentCreate(req request.CommentCreate) error {
-	return global.DB.Create(&database.Comment{
-		ArticleID: req.ArticleID,
-		PID:       req.PID,
-		UserUUID:  req.UserUUID,
-		Content:   req.Content,
-	}).Error
+	return global.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&database.Comment{
+			ArticleID: req.ArticleID,
+			PID:       req.PID,
+			UserUUID:  req.UserUUID,
+			Content:   req.Content,
+		}).Error; err != nil {
+			return err
+		}
+		// 文章评论数由后台任务同步到 ES
+		return enqueueCounter(tx, req.ArticleID, "comments", 1)
+	})
 }
 
 func (commentService *CommentService) CommentDelete(c *gin.Context, req request.CommentDelete) error {
@@ -41,10 +41,15 @@ func (commentService *CommentService) DeleteCommentAndChildren(tx *gorm.DB, comm
 		}
 	}
 
-	if err := tx.Delete(&database.Comment{}, commentID).Error; err != nil {
+	var comment database.Comment
+	if err := tx.Take(&comment, commentID).Error; err != nil {
 		return err
 	}
-	return nil
+	if err := tx.Delete(&comment).Error; err != nil {
+		return err
+	}
+	// 文章评论数由后台任务同步到 ES
+	return enqueueCounter(tx, comment.ArticleID, "comments", -1)
 }
 
 func (commentService *CommentService) FindChildCommentsIDByRootCommentUserUUID(comments []database.Comment) map[uint]struct{} {
@@ -1,6 +1,7 @@
 package service
 
 import (
+	"errors"
 	"server/config"
 	"server/global"
 	"server/model/appTypes"
@@ -75,3 +76,18 @@ func (configService *ConfigService) UpdateGaode(gaode config.Gaode) error {
 	global.Config.Gaode = gaode
 	return utils.SaveYAML()
 }
+
+// UpdateTwoFactor 更新两步验证配置，开启管理员强制两步验证前当前管理员必须已启用两步验证，避免无法再访问管理接口
+func (configService *ConfigService) UpdateTwoFactor(twoFactor config.TwoFactor, userID uint) error {
+	if twoFactor.RequireAdmin {
+		enabled, err := ServiceGroupApp.TwoFactorService.TwoFactorEnabled(userID)
+		if err != nil {
+			return err
+		}
+		if !enabled {
+			return errors.New("enable two-factor authentication for your own account first")
+		}
+	}
+	global.Config.TwoFactor = twoFactor
+	return utils.SaveYAML()
+}
@@ -3,7 +3,6 @@ package service
 type ServiceGroup struct {
 	EsService
 	BaseService
-	JwtService
 	GaodeService
 	UserService
 	QQService
@@ -17,6 +16,12 @@ type ServiceGroup struct {
 	HotSearchService
 	CalendarService
 	ConfigService
+	FeedService
+	SitemapService
+	SeriesService
+	BackupService
+	TwoFactorService
+	SessionService
 }
 
 var ServiceGroupApp = new(ServiceGroup)
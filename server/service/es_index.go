@@ -2,7 +2,11 @@ package service
 
 import (
 	"context"
+	"fmt"
 	"server/global"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
 )
@@ -27,3 +31,152 @@ func (esService *EsService) IndexDelete(indexName string) error {
 func (esService *EsService) IndexExists(indexName string) (bool, error) {
 	return global.ESClient.Indices.Exists(indexName).Do(context.TODO())
 }
+
+// IndexPutMapping 将映射中的字段添加到已有的索引中，索引不存在时不做任何操作
+// 已有字段的类型无法修改，发生冲突时需要使用 --es-reindex 重建索引
+func (esService *EsService) IndexPutMapping(alias string, mapping *types.TypeMapping) error {
+	indices, err := esService.AliasIndices(alias)
+	if err != nil || len(indices) == 0 {
+		return err
+	}
+	_, err = global.ESClient.Indices.PutMapping(alias).Properties(mapping.Properties).Do(context.TODO())
+	return err
+}
+
+// IndexCreateWithAlias 创建一个带版本号的物理索引，并将别名指向该索引，返回物理索引的名称
+func (esService *EsService) IndexCreateWithAlias(alias string, mapping *types.TypeMapping) (string, error) {
+	index := versionedIndexName(alias)
+	_, err := global.ESClient.Indices.Create(index).
+		Mappings(mapping).
+		Aliases(map[string]types.Alias{alias: {}}).
+		Do(context.TODO())
+	return index, err
+}
+
+// IndexDeleteWithAlias 删除别名指向的所有物理索引，兼容直接以别名命名的旧索引
+func (esService *EsService) IndexDeleteWithAlias(alias string) error {
+	indices, err := esService.AliasIndices(alias)
+	if err != nil {
+		return err
+	}
+	for _, index := range indices {
+		if err := esService.IndexDelete(index); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AliasIndices 返回别名当前指向的物理索引
+// 如果该名称是一个普通的索引而不是别名，则返回该索引本身
+func (esService *EsService) AliasIndices(alias string) ([]string, error) {
+	isAlias, err := global.ESClient.Indices.ExistsAlias(alias).Do(context.TODO())
+	if err != nil {
+		return nil, err
+	}
+	if !isAlias {
+		exists, err := esService.IndexExists(alias)
+		if err != nil || !exists {
+			return nil, err
+		}
+		return []string{alias}, nil
+	}
+
+	res, err := global.ESClient.Indices.GetAlias().Name(alias).Do(context.TODO())
+	if err != nil {
+		return nil, err
+	}
+	indices := make([]string, 0, len(res))
+	for index := range res {
+		indices = append(indices, index)
+	}
+	sort.Strings(indices)
+	return indices, nil
+}
+
+// Reindex 使用当前的映射重建索引，在不中断读取的情况下将别名切换到新的索引
+// 返回新的物理索引、切换前别名指向的旧索引以及复制的文档数量
+// 复制期间旧索引被设置为只读，避免写入的数据在切换后丢失
+func (esService *EsService) Reindex(alias string, mapping *types.TypeMapping) (string, []string, int64, error) {
+	oldIndices, err := esService.AliasIndices(alias)
+	if err != nil {
+		return "", nil, 0, err
+	}
+	if len(oldIndices) == 0 {
+		// 索引不存在时直接创建
+		index, err := esService.IndexCreateWithAlias(alias, mapping)
+		return index, nil, 0, err
+	}
+	isAlias, err := global.ESClient.Indices.ExistsAlias(alias).Do(context.TODO())
+	if err != nil {
+		return "", nil, 0, err
+	}
+
+	newIndex := versionedIndexName(alias)
+	if _, err := global.ESClient.Indices.Create(newIndex).Mappings(mapping).Do(context.TODO()); err != nil {
+		return "", nil, 0, err
+	}
+
+	// 禁止写入旧索引，复制失败时恢复写入并删除新索引
+	if err := esService.setWriteBlock(oldIndices, true); err != nil {
+		_ = esService.IndexDelete(newIndex)
+		return "", nil, 0, err
+	}
+	rollback := func() {
+		_ = esService.setWriteBlock(oldIndices, false)
+		_ = esService.IndexDelete(newIndex)
+	}
+
+	res, err := global.ESClient.Reindex().
+		Source(&types.ReindexSource{Index: oldIndices}).
+		Dest(&types.ReindexDestination{Index: newIndex}).
+		Refresh(true).
+		WaitForCompletion(true).
+		Do(context.TODO())
+	if err != nil {
+		rollback()
+		return "", nil, 0, err
+	}
+	if len(res.Failures) > 0 {
+		rollback()
+		return "", nil, 0, fmt.Errorf("failed to copy %d documents, the first failed document id is %s", len(res.Failures), res.Failures[0].Id)
+	}
+
+	// 原子地将别名从旧索引切换到新索引
+	// 旧版本直接以别名命名的索引必须在同一个操作中删除，才能创建同名的别名
+	actions := []types.IndicesAction{{Add: &types.AddAction{Index: &newIndex, Alias: &alias}}}
+	for _, index := range oldIndices {
+		if isAlias {
+			actions = append(actions, types.IndicesAction{Remove: &types.RemoveAction{Index: &index, Alias: &alias}})
+		} else {
+			actions = append(actions, types.IndicesAction{RemoveIndex: &types.RemoveIndexAction{Index: &index}})
+		}
+	}
+	if _, err := global.ESClient.Indices.UpdateAliases().Actions(actions...).Do(context.TODO()); err != nil {
+		rollback()
+		return "", nil, 0, err
+	}
+
+	var total int64
+	if res.Total != nil {
+		total = *res.Total
+	}
+	if !isAlias {
+		return newIndex, nil, total, nil
+	}
+	return newIndex, oldIndices, total, nil
+}
+
+// setWriteBlock 设置索引是否禁止写入
+func (esService *EsService) setWriteBlock(indices []string, block bool) error {
+	_, err := global.ESClient.Indices.PutSettings().
+		Indices(strings.Join(indices, ",")).
+		Blocks(&types.IndexSettingBlocks{Write: block}).
+		Do(context.TODO())
+	return err
+}
+
+// versionedIndexName 生成带版本号的物理索引名称，例如 article_index_v20240101120000
+func versionedIndexName(alias string) string {
+	return alias + "_v" + time.Now().Format("20060102150405")
+}
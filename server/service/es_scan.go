@@ -0,0 +1,99 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"server/global"
+	"server/model/other"
+	"slices"
+
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types/enums/sortorder"
+)
+
+// ScanDocuments 按创建时间顺序分批遍历索引中的原始文档，从 position 之后开始，每批处理成功后更新 position
+// fn 的第二个参数为处理完该批文档之后的遍历位置，可用于记录断点
+func (esService *EsService) ScanDocuments(indexName string, position *other.EsPosition, fn func(batch []other.Data, next other.EsPosition) error) error {
+	// 从指定位置继续时，从该创建时间开始查询，并跳过该时间点上已经处理的文档
+	query := &types.Query{MatchAll: &types.MatchAllQuery{}}
+	if position.CreatedAt != "" {
+		query = &types.Query{Range: map[string]types.RangeQuery{"created_at": types.DateRangeQuery{Gte: &position.CreatedAt}}}
+	}
+	processed := make(map[string]bool, len(position.IDs))
+	for _, id := range position.IDs {
+		processed[id] = true
+	}
+
+	return scanHits(indexName, query, nil, func(hits []types.Hit) error {
+		var batch []other.Data
+		for _, hit := range hits {
+			if !processed[*hit.Id_] {
+				batch = append(batch, other.Data{ID: hit.Id_, Doc: hit.Source_})
+			}
+		}
+		if len(batch) == 0 {
+			return nil
+		}
+		next := advancePosition(*position, batch)
+		if err := fn(batch, next); err != nil {
+			return err
+		}
+		*position = next
+		return nil
+	})
+}
+
+// scanHits 使用 point in time 和 search_after 按创建时间顺序分批遍历索引中符合条件的文档，sourceIncludes 为空时返回全部字段
+func scanHits(indexName string, query *types.Query, sourceIncludes []string, fn func(hits []types.Hit) error) error {
+	// 使用 point in time 保证遍历过程中数据的一致性
+	pit, err := global.ESClient.OpenPointInTime(indexName).KeepAlive("5m").Do(context.TODO())
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_, _ = global.ESClient.ClosePointInTime().Id(pit.Id).Do(context.TODO())
+	}()
+
+	var searchAfter []types.FieldValue
+	for {
+		req := global.ESClient.Search().
+			Pit(&types.PointInTimeReference{Id: pit.Id, KeepAlive: "5m"}).
+			Query(query).
+			Sort(types.SortOptions{SortOptions: map[string]types.FieldSort{"created_at": {Order: &sortorder.Asc}}}).
+			Size(global.Config.ES.Batch())
+		if len(sourceIncludes) > 0 {
+			req = req.SourceIncludes_(sourceIncludes...)
+		}
+		if searchAfter != nil {
+			req = req.SearchAfter(searchAfter...)
+		}
+		res, err := req.Do(context.TODO())
+		if err != nil {
+			return err
+		}
+		if len(res.Hits.Hits) == 0 {
+			return nil
+		}
+		if err := fn(res.Hits.Hits); err != nil {
+			return err
+		}
+		searchAfter = res.Hits.Hits[len(res.Hits.Hits)-1].Sort
+	}
+}
+
+// advancePosition 返回处理完 batch 之后的遍历位置
+func advancePosition(position other.EsPosition, batch []other.Data) other.EsPosition {
+	position.IDs = slices.Clone(position.IDs)
+	for _, data := range batch {
+		var doc struct {
+			CreatedAt string `json:"created_at"`
+		}
+		_ = json.Unmarshal(data.Doc, &doc)
+		if doc.CreatedAt != position.CreatedAt {
+			position.CreatedAt = doc.CreatedAt
+			position.IDs = nil
+		}
+		position.IDs = append(position.IDs, *data.ID)
+	}
+	return position
+}
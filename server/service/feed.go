@@ -0,0 +1,238 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"server/global"
+	"server/model/elasticsearch"
+	"server/model/other"
+	"server/model/request"
+	"server/model/response"
+	"strings"
+	"time"
+
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types/enums/fieldtype"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types/enums/sortorder"
+)
+
+// feedSize 订阅源中包含的文章数量
+const feedSize = 20
+
+type FeedService struct {
+}
+
+// FeedRSS 生成 RSS 2.0 订阅源，同时返回订阅源的最后修改时间
+func (feedService *FeedService) FeedRSS(req request.WebsiteFeed, feedURL string) ([]byte, time.Time, error) {
+	hits, lastModified, err := feedService.FeedArticles(req)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	site := siteURL()
+	rss := other.RSS{
+		Version: "2.0",
+		Atom:    "http://www.w3.org/2005/Atom",
+		Channel: other.RSSChannel{
+			Title:       feedTitle(req),
+			Link:        site,
+			Description: global.Config.Website.Description,
+			Language:    "zh-CN",
+			AtomLink:    other.AtomLink{Href: feedURL, Rel: "self", Type: "application/rss+xml"},
+		},
+	}
+	if !lastModified.IsZero() {
+		rss.Channel.LastBuildDate = lastModified.Format(time.RFC1123Z)
+	}
+	for _, hit := range hits {
+		link := articleURL(hit.Id_)
+		rss.Channel.Items = append(rss.Channel.Items, other.RSSItem{
+			Title:       hit.Source_.Title,
+			Link:        link,
+			Description: hit.Source_.Abstract,
+			GUID:        other.RSSGUID{IsPermaLink: true, Value: link},
+			PubDate:     articlePublishTime(hit.Source_).Format(time.RFC1123Z),
+			Categories:  append([]string{hit.Source_.Category}, hit.Source_.Tags...),
+		})
+	}
+
+	data, err := xml.MarshalIndent(rss, "", "  ")
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	return append([]byte(xml.Header), data...), lastModified, nil
+}
+
+// FeedAtom 生成 Atom 1.0 订阅源，同时返回订阅源的最后修改时间
+func (feedService *FeedService) FeedAtom(req request.WebsiteFeed, feedURL string) ([]byte, time.Time, error) {
+	hits, lastModified, err := feedService.FeedArticles(req)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	// Atom 要求必须提供更新时间，没有文章时使用当前时间
+	updated := lastModified
+	if updated.IsZero() {
+		updated = time.Now()
+	}
+
+	site := siteURL()
+	feed := other.AtomFeed{
+		Title:    feedTitle(req),
+		Subtitle: global.Config.Website.Description,
+		ID:       feedURL,
+		Updated:  updated.Format(time.RFC3339),
+		Links: []other.AtomLink{
+			{Href: site, Rel: "alternate", Type: "text/html"},
+			{Href: feedURL, Rel: "self", Type: "application/atom+xml"},
+		},
+		Author: other.AtomAuthor{Name: global.Config.Website.Name},
+	}
+	for _, hit := range hits {
+		link := articleURL(hit.Id_)
+		entry := other.AtomEntry{
+			Title:     hit.Source_.Title,
+			ID:        link,
+			Links:     []other.AtomLink{{Href: link, Rel: "alternate", Type: "text/html"}},
+			Published: articlePublishTime(hit.Source_).Format(time.RFC3339),
+			Updated:   parseArticleTime(hit.Source_.UpdatedAt).Format(time.RFC3339),
+			Summary:   hit.Source_.Abstract,
+		}
+		for _, category := range append([]string{hit.Source_.Category}, hit.Source_.Tags...) {
+			entry.Categories = append(entry.Categories, other.AtomCategory{Term: category})
+		}
+		feed.Entries = append(feed.Entries, entry)
+	}
+
+	data, err := xml.MarshalIndent(feed, "", "  ")
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	return append([]byte(xml.Header), data...), lastModified, nil
+}
+
+// FeedJSON 生成 JSON Feed 1.1 订阅源，同时返回订阅源的最后修改时间
+func (feedService *FeedService) FeedJSON(req request.WebsiteFeed, feedURL string) ([]byte, time.Time, error) {
+	hits, lastModified, err := feedService.FeedArticles(req)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	feed := other.JSONFeed{
+		Version:     "https://jsonfeed.org/version/1.1",
+		Title:       feedTitle(req),
+		HomePageURL: siteURL(),
+		FeedURL:     feedURL,
+		Description: global.Config.Website.Description,
+		Authors:     []other.JSONFeedAuthor{{Name: global.Config.Website.Name}},
+		Language:    "zh-CN",
+		Items:       []other.JSONFeedItem{},
+	}
+	for _, hit := range hits {
+		link := articleURL(hit.Id_)
+		feed.Items = append(feed.Items, other.JSONFeedItem{
+			ID:            link,
+			URL:           link,
+			Title:         hit.Source_.Title,
+			ContentText:   hit.Source_.Content,
+			ContentHTML:   hit.Source_.ContentHTML,
+			Summary:       hit.Source_.Abstract,
+			Image:         absoluteURL(hit.Source_.Cover),
+			DatePublished: articlePublishTime(hit.Source_).Format(time.RFC3339),
+			DateModified:  parseArticleTime(hit.Source_.UpdatedAt).Format(time.RFC3339),
+			Tags:          hit.Source_.Tags,
+		})
+	}
+
+	data, err := json.Marshal(feed)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	return data, lastModified, nil
+}
+
+// FeedArticles 获取最新发布的文章，可以按类别或标签筛选，同时返回这些文章中最晚的更新时间
+func (feedService *FeedService) FeedArticles(req request.WebsiteFeed) ([]response.ArticleHit, time.Time, error) {
+	boolQuery := &types.BoolQuery{
+		Filter: []types.Query{publishedQuery()},
+	}
+	if req.Category != "" {
+		boolQuery.Filter = append(boolQuery.Filter, types.Query{Term: map[string]types.TermQuery{"category": {Value: req.Category}}})
+	}
+	if req.Tag != "" {
+		boolQuery.Filter = append(boolQuery.Filter, types.Query{Term: map[string]types.TermQuery{"tags": {Value: req.Tag}}})
+	}
+
+	res, err := global.ESClient.Search().
+		Index(elasticsearch.ArticleIndex()).
+		Query(&types.Query{Bool: boolQuery}).
+		// 按发布时间排序，没有发布时间的旧文章排在后面，按创建时间排序
+		Sort(
+			types.SortOptions{SortOptions: map[string]types.FieldSort{"publish_at": {Order: &sortorder.Desc, Missing: "_last", UnmappedType: &fieldtype.Date}}},
+			types.SortOptions{SortOptions: map[string]types.FieldSort{"created_at": {Order: &sortorder.Desc}}},
+		).
+		Size(feedSize).
+		Do(context.TODO())
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	var hits []response.ArticleHit
+	var lastModified time.Time
+	for _, hit := range res.Hits.Hits {
+		var article elasticsearch.Article
+		if err := json.Unmarshal(hit.Source_, &article); err != nil {
+			return nil, time.Time{}, err
+		}
+		if t := parseArticleTime(article.UpdatedAt); t.After(lastModified) {
+			lastModified = t
+		}
+		hits = append(hits, response.ArticleHit{Id_: *hit.Id_, Source_: article})
+	}
+	return hits, lastModified, nil
+}
+
+// feedTitle 返回订阅源标题，按类别或标签筛选时附加筛选条件
+func feedTitle(req request.WebsiteFeed) string {
+	title := global.Config.Website.Title
+	if req.Category != "" {
+		title += " - " + req.Category
+	}
+	if req.Tag != "" {
+		title += " - #" + req.Tag
+	}
+	return title
+}
+
+// siteURL 返回网站地址，不包含末尾的斜杠
+func siteURL() string {
+	return strings.TrimRight(global.Config.Website.URL, "/")
+}
+
+// articleURL 返回文章页面的完整地址
+func articleURL(id string) string {
+	return siteURL() + "/article/" + id
+}
+
+// absoluteURL 将站内的相对路径转换为完整地址
+func absoluteURL(url string) string {
+	if strings.HasPrefix(url, "/") {
+		return siteURL() + url
+	}
+	return url
+}
+
+// articlePublishTime 返回文章的发布时间，没有发布时间的旧文章使用创建时间
+func articlePublishTime(article elasticsearch.Article) time.Time {
+	if t := parseArticleTime(article.PublishAt); !t.IsZero() {
+		return t
+	}
+	return parseArticleTime(article.CreatedAt)
+}
+
+// parseArticleTime 解析文章中保存的时间，格式为 yyyy-MM-dd HH:mm:ss
+func parseArticleTime(value string) time.Time {
+	t, _ := time.ParseInLocation("2006-01-02 15:04:05", value, time.Local)
+	return t
+}
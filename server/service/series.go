@@ -0,0 +1,270 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"server/global"
+	"server/model/appTypes"
+	"server/model/database"
+	"server/model/elasticsearch"
+	"server/model/other"
+	"server/model/request"
+	"server/model/response"
+	"server/utils"
+
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
+	"gorm.io/gorm"
+)
+
+type SeriesService struct {
+}
+
+// SeriesInfo 获取系列信息，以及系列中按顺序排列的已发布文章
+func (seriesService *SeriesService) SeriesInfo(req request.SeriesInfo) (response.SeriesInfo, error) {
+	var series database.Series
+	if err := global.DB.Preload("Articles", func(db *gorm.DB) *gorm.DB {
+		return db.Order("sort")
+	}).Take(&series, req.ID).Error; err != nil {
+		return response.SeriesInfo{}, err
+	}
+
+	articleList, err := seriesService.publishedArticles(series.Articles)
+	if err != nil {
+		return response.SeriesInfo{}, err
+	}
+	return response.SeriesInfo{
+		ID:          series.ID,
+		CreatedAt:   series.CreatedAt,
+		UpdatedAt:   series.UpdatedAt,
+		Title:       series.Title,
+		Description: series.Description,
+		Cover:       series.Cover,
+		ArticleList: articleList,
+	}, nil
+}
+
+func (seriesService *SeriesService) SeriesCreate(req request.SeriesCreate) error {
+	if err := seriesService.checkArticleIDs(req.ArticleIDs); err != nil {
+		return err
+	}
+	seriesToCreate := database.Series{
+		Title:       req.Title,
+		Description: req.Description,
+		Cover:       req.Cover,
+		Articles:    seriesArticles(req.ArticleIDs),
+	}
+	return global.DB.Transaction(func(tx *gorm.DB) error {
+		if err := utils.ChangeImagesCategory(tx, []string{seriesToCreate.Cover}, appTypes.Cover); err != nil {
+			return err
+		}
+
+		return tx.Create(&seriesToCreate).Error
+	})
+}
+
+func (seriesService *SeriesService) SeriesDelete(req request.SeriesDelete) error {
+	if len(req.IDs) == 0 {
+		return nil
+	}
+	return global.DB.Transaction(func(tx *gorm.DB) error {
+		for _, id := range req.IDs {
+			var seriesToDelete database.Series
+			if err := tx.Take(&seriesToDelete, id).Error; err != nil {
+				return err
+			}
+			if err := utils.InitImagesCategory(tx, []string{seriesToDelete.Cover}); err != nil {
+				return err
+			}
+			if err := tx.Where("series_id = ?", id).Delete(&database.SeriesArticle{}).Error; err != nil {
+				return err
+			}
+			if err := tx.Delete(&seriesToDelete).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (seriesService *SeriesService) SeriesUpdate(req request.SeriesUpdate) error {
+	if err := seriesService.checkArticleIDs(req.ArticleIDs); err != nil {
+		return err
+	}
+	return global.DB.Transaction(func(tx *gorm.DB) error {
+		var oldSeries database.Series
+		if err := tx.Take(&oldSeries, req.ID).Error; err != nil {
+			return err
+		}
+
+		// 同时更新图片表中的图片类别
+		if req.Cover != oldSeries.Cover {
+			if err := utils.InitImagesCategory(tx, []string{oldSeries.Cover}); err != nil {
+				return err
+			}
+			if err := utils.ChangeImagesCategory(tx, []string{req.Cover}, appTypes.Cover); err != nil {
+				return err
+			}
+		}
+
+		// 文章列表整体替换，按新的顺序重新写入
+		if err := tx.Where("series_id = ?", req.ID).Delete(&database.SeriesArticle{}).Error; err != nil {
+			return err
+		}
+		articles := seriesArticles(req.ArticleIDs)
+		for i := range articles {
+			articles[i].SeriesID = req.ID
+		}
+		if len(articles) > 0 {
+			if err := tx.Create(&articles).Error; err != nil {
+				return err
+			}
+		}
+
+		return tx.Model(&oldSeries).Updates(map[string]interface{}{
+			"title":       req.Title,
+			"description": req.Description,
+			"cover":       req.Cover,
+		}).Error
+	})
+}
+
+func (seriesService *SeriesService) SeriesList(info request.SeriesList) (interface{}, int64, error) {
+	db := global.DB
+
+	if info.Title != nil {
+		db = db.Where("title LIKE ?", "%"+*info.Title+"%")
+	}
+
+	option := other.MySQLOption{
+		PageInfo: info.PageInfo,
+		Where:    db,
+		Preload:  []string{"Articles"},
+	}
+
+	return utils.MySQLPagination(&database.Series{}, option)
+}
+
+// ArticleSeriesNav 获取文章所在的所有系列，以及文章在每个系列中的上一篇和下一篇，未发布的文章会被跳过
+func (seriesService *SeriesService) ArticleSeriesNav(articleID string) ([]response.ArticleSeriesNav, error) {
+	var seriesIDs []uint
+	if err := global.DB.Model(&database.SeriesArticle{}).Where("article_id = ?", articleID).Distinct().Pluck("series_id", &seriesIDs).Error; err != nil {
+		return nil, err
+	}
+	if len(seriesIDs) == 0 {
+		return nil, nil
+	}
+
+	var seriesList []database.Series
+	if err := global.DB.Preload("Articles", func(db *gorm.DB) *gorm.DB {
+		return db.Order("sort")
+	}).Where("id IN ?", seriesIDs).Order("id").Find(&seriesList).Error; err != nil {
+		return nil, err
+	}
+
+	var navs []response.ArticleSeriesNav
+	for _, series := range seriesList {
+		hits, err := seriesService.publishedArticles(series.Articles)
+		if err != nil {
+			return nil, err
+		}
+		for i, hit := range hits {
+			if hit.Id_ != articleID {
+				continue
+			}
+			nav := response.ArticleSeriesNav{
+				SeriesID: series.ID,
+				Title:    series.Title,
+				Position: i + 1,
+				Total:    len(hits),
+			}
+			if i > 0 {
+				nav.Prev = seriesArticleLink(hits[i-1])
+			}
+			if i < len(hits)-1 {
+				nav.Next = seriesArticleLink(hits[i+1])
+			}
+			navs = append(navs, nav)
+			break
+		}
+	}
+	return navs, nil
+}
+
+// RemoveArticle 将文章从所有系列中移除，删除文章时调用
+func (seriesService *SeriesService) RemoveArticle(tx *gorm.DB, articleID string) error {
+	return tx.Where("article_id = ?", articleID).Delete(&database.SeriesArticle{}).Error
+}
+
+// publishedArticles 按系列中的顺序返回已发布的文章，不包含文章内容
+func (seriesService *SeriesService) publishedArticles(articles []database.SeriesArticle) ([]response.ArticleHit, error) {
+	if len(articles) == 0 {
+		return []response.ArticleHit{}, nil
+	}
+	ids := make([]string, 0, len(articles))
+	for _, article := range articles {
+		ids = append(ids, article.ArticleID)
+	}
+
+	res, err := global.ESClient.Search().
+		Index(elasticsearch.ArticleIndex()).
+		Query(&types.Query{
+			Bool: &types.BoolQuery{
+				Filter: []types.Query{
+					{Ids: &types.IdsQuery{Values: ids}},
+					publishedQuery(),
+				},
+			},
+		}).
+		SourceExcludes_("content", "content_html", "toc").
+		Size(len(ids)).
+		Do(context.TODO())
+	if err != nil {
+		return nil, err
+	}
+
+	found := make(map[string]elasticsearch.Article, len(res.Hits.Hits))
+	for _, hit := range res.Hits.Hits {
+		var article elasticsearch.Article
+		if err := json.Unmarshal(hit.Source_, &article); err != nil {
+			return nil, err
+		}
+		found[*hit.Id_] = article
+	}
+
+	hits := []response.ArticleHit{}
+	for _, id := range ids {
+		if article, ok := found[id]; ok {
+			hits = append(hits, response.ArticleHit{Id_: id, Source_: article})
+		}
+	}
+	return hits, nil
+}
+
+// checkArticleIDs 校验系列中的文章都存在且没有重复
+func (seriesService *SeriesService) checkArticleIDs(ids []string) error {
+	seen := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		if seen[id] {
+			return errors.New("duplicate article in series")
+		}
+		seen[id] = true
+		if _, err := ServiceGroupApp.ArticleService.Get(id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// seriesArticles 根据文章 ID 列表生成有序的关联记录
+func seriesArticles(ids []string) []database.SeriesArticle {
+	articles := make([]database.SeriesArticle, 0, len(ids))
+	for i, id := range ids {
+		articles = append(articles, database.SeriesArticle{ArticleID: id, Sort: i + 1})
+	}
+	return articles
+}
+
+func seriesArticleLink(hit response.ArticleHit) *response.SeriesArticleLink {
+	return &response.SeriesArticleLink{ID: hit.Id_, Title: hit.Source_.Title, Slug: hit.Source_.Slug}
+}
@@ -0,0 +1,192 @@
+package service
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"server/global"
+	"server/model/database"
+	"server/model/response"
+	"server/utils"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type SessionService struct {
+}
+
+const (
+	sessionTouchInterval   = time.Minute         // 最近活跃时间的最小更新间隔
+	sessionRetention       = 30 * 24 * time.Hour // 过期或注销的会话保留的时间
+	refreshTokenReuseGrace = 30 * time.Second    // 刷新令牌被轮换后，同一客户端的并发请求仍可使用旧令牌的时间
+)
+
+var (
+	ErrSessionInvalid     = errors.New("the session has expired or been revoked")
+	ErrRefreshTokenReused = errors.New("the refresh token has already been used")
+)
+
+// refreshTokenHash 计算刷新令牌的哈希值，数据库中只保存哈希值
+func refreshTokenHash(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// SessionCreate 为一次登录创建会话，未开启多点登录时注销该用户的其他会话
+func (sessionService *SessionService) SessionCreate(session database.UserSession, refreshToken string) error {
+	session.RefreshTokenHash = refreshTokenHash(refreshToken)
+	session.LastSeenAt = time.Now()
+	if err := global.DB.Create(&session).Error; err != nil {
+		return err
+	}
+	if global.Config.System.UseMultipoint {
+		return sessionService.SessionRevokeAll(session.UserID, session.SessionID)
+	}
+	return nil
+}
+
+// SessionUpdateDevice 使用登录日志中解析出的地址和设备信息补充会话信息
+func (sessionService *SessionService) SessionUpdateDevice(sessionID string, login database.Login) error {
+	return global.DB.Model(&database.UserSession{}).Where("session_id = ?", sessionID).Updates(map[string]interface{}{
+		"address":      login.Address,
+		"os":           login.OS,
+		"device_info":  login.DeviceInfo,
+		"browser_info": login.BrowserInfo,
+	}).Error
+}
+
+// SessionRotate 轮换会话的刷新令牌，旧的刷新令牌随即失效，返回是否完成了轮换
+// 刚被轮换的令牌在宽限期内再次出现时视为同一客户端的并发刷新，此时不再轮换，调用方应继续使用已下发的新令牌；
+// 其余已失效的令牌再次出现时视为令牌被盗用，整个会话会被注销
+func (sessionService *SessionService) SessionRotate(sessionID, refreshToken, newRefreshToken, ip string) (bool, error) {
+	oldHash := refreshTokenHash(refreshToken)
+	now := time.Now()
+	result := global.DB.Model(&database.UserSession{}).
+		Where("session_id = ? AND refresh_token_hash = ? AND revoked_at IS NULL AND expires_at > ?", sessionID, oldHash, now).
+		Updates(map[string]interface{}{
+			"refresh_token_hash": refreshTokenHash(newRefreshToken),
+			"previous_hash":      oldHash,
+			"rotated_at":         now,
+			"last_seen_at":       now,
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	if result.RowsAffected == 1 {
+		return true, nil
+	}
+
+	// 提交的不是会话当前的刷新令牌
+	var session database.UserSession
+	err := global.DB.Where("session_id = ?", sessionID).First(&session).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, ErrSessionInvalid
+	}
+	if err != nil {
+		return false, err
+	}
+	if session.RevokedAt != nil || !session.ExpiresAt.After(now) {
+		return false, ErrSessionInvalid
+	}
+	if session.RotatedAt != nil && now.Sub(*session.RotatedAt) < refreshTokenReuseGrace &&
+		subtle.ConstantTimeCompare([]byte(session.PreviousHash), []byte(oldHash)) == 1 {
+		return false, nil
+	}
+
+	// 令牌签名有效但已被使用过，注销整个会话
+	global.Log.Warn("Security event: refresh token reuse detected, revoking the session",
+		zap.Uint("user_id", session.UserID),
+		zap.String("session_id", session.SessionID),
+		zap.String("ip", ip),
+		zap.String("session_ip", session.IP))
+	if err := sessionService.SessionRevoke(session.UserID, session.SessionID); err != nil {
+		return false, err
+	}
+	return false, ErrRefreshTokenReused
+}
+
+// SessionActive 判断访问令牌所属的会话是否仍然有效，并按间隔更新会话的最近活跃时间
+func (sessionService *SessionService) SessionActive(sessionID string) bool {
+	if sessionID == "" {
+		return false
+	}
+	if revoked, _ := global.Redis.Exists("session_revoked:" + sessionID).Result(); revoked > 0 {
+		return false
+	}
+	if first, err := global.Redis.SetNX("session_seen:"+sessionID, 1, sessionTouchInterval).Result(); err == nil && first {
+		global.DB.Model(&database.UserSession{}).Where("session_id = ?", sessionID).Update("last_seen_at", time.Now())
+	}
+	return true
+}
+
+// SessionList 获取用户当前有效的会话，currentSessionID 对应的会话会被标记为当前会话
+func (sessionService *SessionService) SessionList(userID uint, currentSessionID string) ([]response.UserSession, error) {
+	var sessions []database.UserSession
+	err := global.DB.Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, time.Now()).
+		Order("last_seen_at DESC").Find(&sessions).Error
+	if err != nil {
+		return nil, err
+	}
+	list := make([]response.UserSession, len(sessions))
+	for i, session := range sessions {
+		list[i] = response.UserSession{UserSession: session, Current: session.SessionID == currentSessionID}
+	}
+	return list, nil
+}
+
+// SessionRevoke 注销用户自己的一个会话
+func (sessionService *SessionService) SessionRevoke(userID uint, sessionID string) error {
+	result := global.DB.Model(&database.UserSession{}).
+		Where("user_id = ? AND session_id = ? AND revoked_at IS NULL", userID, sessionID).
+		Update("revoked_at", time.Now())
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("session not found")
+	}
+	return markSessionsRevoked([]string{sessionID})
+}
+
+// SessionRevokeAll 注销用户的所有会话，except 不为空时保留该会话
+func (sessionService *SessionService) SessionRevokeAll(userID uint, except string) error {
+	db := global.DB.Model(&database.UserSession{}).Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, time.Now())
+	if except != "" {
+		db = db.Where("session_id <> ?", except)
+	}
+	var sessionIDs []string
+	if err := db.Pluck("session_id", &sessionIDs).Error; err != nil {
+		return err
+	}
+	if len(sessionIDs) == 0 {
+		return nil
+	}
+	if err := global.DB.Model(&database.UserSession{}).Where("session_id IN ?", sessionIDs).Update("revoked_at", time.Now()).Error; err != nil {
+		return err
+	}
+	return markSessionsRevoked(sessionIDs)
+}
+
+// markSessionsRevoked 在 Redis 中标记已注销的会话，使其访问令牌在过期前立即失效
+func markSessionsRevoked(sessionIDs []string) error {
+	ttl, err := utils.ParseDuration(global.Config.Jwt.AccessTokenExpiryTime)
+	if err != nil {
+		return err
+	}
+	for _, sessionID := range sessionIDs {
+		if err := global.Redis.Set("session_revoked:"+sessionID, 1, ttl).Err(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SessionCleanup 删除过期或注销超过保留时间的会话，返回删除的数量
+func (sessionService *SessionService) SessionCleanup() (int64, error) {
+	before := time.Now().Add(-sessionRetention)
+	result := global.DB.Unscoped().Where("expires_at < ? OR revoked_at < ?", before, before).Delete(&database.UserSession{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,215 @@
+package service
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"server/global"
+	"server/model/database"
+	"server/model/elasticsearch"
+	"server/model/other"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
+	"github.com/go-redis/redis"
+)
+
+// sitemapCacheKey 站点地图在 Redis 中缓存使用的哈希表，每个字段对应一个站点地图文件
+const sitemapCacheKey = "sitemap"
+
+// sitemapCacheTTL 站点地图缓存的有效期
+const sitemapCacheTTL = 24 * time.Hour
+
+// sitemapIndexName 站点地图索引文件的名称
+const sitemapIndexName = "sitemap.xml"
+
+var sitemapNameRegexp = regexp.MustCompile(`^(articles|categories|tags)-(\d+)\.xml$`)
+
+// sitemapMutex 避免缓存失效时多个请求同时重新生成站点地图
+var sitemapMutex sync.Mutex
+
+var ErrSitemapNotFound = errors.New("sitemap not found")
+
+type SitemapService struct {
+}
+
+// SitemapIndex 获取站点地图索引
+func (sitemapService *SitemapService) SitemapIndex() ([]byte, error) {
+	return sitemapService.Sitemap(sitemapIndexName)
+}
+
+// Sitemap 根据文件名获取站点地图，例如 articles-1.xml，只有缓存中没有站点地图索引时才会重新生成
+func (sitemapService *SitemapService) Sitemap(name string) ([]byte, error) {
+	if name != sitemapIndexName && !sitemapNameRegexp.MatchString(name) {
+		return nil, ErrSitemapNotFound
+	}
+
+	if data, cached, err := cachedSitemap(name); cached || err != nil {
+		return data, err
+	}
+
+	sitemapMutex.Lock()
+	defer sitemapMutex.Unlock()
+	// 等待期间其他请求可能已经生成了站点地图
+	if data, cached, err := cachedSitemap(name); cached || err != nil {
+		return data, err
+	}
+
+	sitemaps, err := sitemapService.GenerateSitemaps()
+	if err != nil {
+		return nil, err
+	}
+	fields := make(map[string]interface{}, len(sitemaps))
+	for k, v := range sitemaps {
+		fields[k] = v
+	}
+	_, err = global.Redis.TxPipelined(func(pipe redis.Pipeliner) error {
+		pipe.Del(sitemapCacheKey)
+		pipe.HMSet(sitemapCacheKey, fields)
+		pipe.Expire(sitemapCacheKey, sitemapCacheTTL)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	data, ok := sitemaps[name]
+	if !ok {
+		return nil, ErrSitemapNotFound
+	}
+	return data, nil
+}
+
+// cachedSitemap 从缓存中获取站点地图，cached 表示缓存中是否有完整的站点地图，
+// 有站点地图索引时缓存即为完整的，此时缓存中没有的文件不存在
+func cachedSitemap(name string) (data []byte, cached bool, err error) {
+	values, err := global.Redis.HMGet(sitemapCacheKey, sitemapIndexName, name).Result()
+	if err != nil {
+		return nil, false, err
+	}
+	if values[0] == nil {
+		return nil, false, nil
+	}
+	value, ok := values[1].(string)
+	if !ok {
+		return nil, true, ErrSitemapNotFound
+	}
+	return []byte(value), true, nil
+}
+
+// ClearSitemapCache 清除站点地图缓存，文章发生变化时调用
+func (sitemapService *SitemapService) ClearSitemapCache() error {
+	return global.Redis.Del(sitemapCacheKey).Err()
+}
+
+// Robots 生成 robots.txt
+func (sitemapService *SitemapService) Robots() string {
+	var builder strings.Builder
+	builder.WriteString("User-agent: *\n")
+	if len(global.Config.Sitemap.Disallow) == 0 {
+		builder.WriteString("Disallow:\n")
+	}
+	for _, path := range global.Config.Sitemap.Disallow {
+		builder.WriteString("Disallow: " + path + "\n")
+	}
+	builder.WriteString("\nSitemap: " + siteURL() + "/" + sitemapIndexName + "\n")
+	return builder.String()
+}
+
+// GenerateSitemaps 生成站点地图索引和所有分页的站点地图，返回文件名到文件内容的映射
+func (sitemapService *SitemapService) GenerateSitemaps() (map[string][]byte, error) {
+	articles, err := sitemapService.articleURLs()
+	if err != nil {
+		return nil, err
+	}
+
+	var categoryList []database.ArticleCategory
+	if err := global.DB.Where("number > 0").Order("category").Find(&categoryList).Error; err != nil {
+		return nil, err
+	}
+	var categories []other.SitemapURL
+	for _, category := range categoryList {
+		categories = append(categories, other.SitemapURL{
+			Loc:        siteURL() + "/search?category=" + url.QueryEscape(category.Category),
+			ChangeFreq: "weekly",
+		})
+	}
+
+	var tagList []database.ArticleTag
+	if err := global.DB.Where("number > 0").Order("tag").Find(&tagList).Error; err != nil {
+		return nil, err
+	}
+	var tags []other.SitemapURL
+	for _, tag := range tagList {
+		tags = append(tags, other.SitemapURL{
+			Loc:        siteURL() + "/search?tag=" + url.QueryEscape(tag.Tag),
+			ChangeFreq: "weekly",
+		})
+	}
+
+	sitemaps := make(map[string][]byte)
+	index := other.SitemapIndex{}
+	for _, group := range []struct {
+		kind string
+		urls []other.SitemapURL
+	}{
+		{"articles", articles},
+		{"categories", categories},
+		{"tags", tags},
+	} {
+		limit := global.Config.Sitemap.Limit()
+		for page := 1; (page-1)*limit < len(group.urls); page++ {
+			urls := group.urls[(page-1)*limit : min(page*limit, len(group.urls))]
+			data, err := marshalSitemap(other.SitemapURLSet{URLs: urls})
+			if err != nil {
+				return nil, err
+			}
+			name := fmt.Sprintf("%s-%d.xml", group.kind, page)
+			sitemaps[name] = data
+
+			// 该分页中最晚的修改时间作为索引中的修改时间
+			lastMod := ""
+			for _, u := range urls {
+				if u.LastMod > lastMod {
+					lastMod = u.LastMod
+				}
+			}
+			index.Sitemaps = append(index.Sitemaps, other.SitemapLoc{Loc: siteURL() + "/sitemap/" + name, LastMod: lastMod})
+		}
+	}
+
+	data, err := marshalSitemap(index)
+	if err != nil {
+		return nil, err
+	}
+	sitemaps[sitemapIndexName] = data
+	return sitemaps, nil
+}
+
+// articleURLs 遍历所有已发布的文章，按创建时间排序生成站点地图链接
+func (sitemapService *SitemapService) articleURLs() ([]other.SitemapURL, error) {
+	var urls []other.SitemapURL
+	query := &types.Query{Bool: &types.BoolQuery{Filter: []types.Query{publishedQuery()}}}
+	err := ServiceGroupApp.ArticleService.Scan(query, []string{"updated_at"}, func(id string, article elasticsearch.Article) error {
+		sitemapURL := other.SitemapURL{Loc: articleURL(id)}
+		if t := parseArticleTime(article.UpdatedAt); !t.IsZero() {
+			sitemapURL.LastMod = t.Format(time.RFC3339)
+		}
+		urls = append(urls, sitemapURL)
+		return nil
+	})
+	return urls, err
+}
+
+// marshalSitemap 将站点地图序列化为带 XML 声明的文档
+func marshalSitemap(v any) ([]byte, error) {
+	data, err := xml.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), data...), nil
+}
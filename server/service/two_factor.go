@@ -0,0 +1,442 @@
+package service
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/hex"
+	"errors"
+	"server/global"
+	"server/model/appTypes"
+	"server/model/database"
+	"server/model/request"
+	"server/model/response"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis"
+	"github.com/pquerna/otp"
+	"github.com/pquerna/otp/totp"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type TwoFactorService struct {
+}
+
+const (
+	twoFactorPeriod           = 30               // TOTP 时间步长，单位为秒
+	twoFactorSkew             = 1                // 允许前后偏差的时间步数量
+	twoFactorRecoveryCodes    = 10               // 每次生成的恢复码数量
+	twoFactorLoginTTL         = 5 * time.Minute  // 第二步登录的有效期
+	twoFactorLoginMaxAttempts = 5                // 第二步登录允许的最大错误次数
+	twoFactorMaxFailures      = 10               // 同一用户在同一 IP 上第二步登录连续错误的次数达到该值后锁定该 IP
+	twoFactorLockout          = 15 * time.Minute // 第二步登录的锁定时间，从最后一次错误开始计算
+	twoFactorUserMaxFailures  = 30               // 同一用户在所有 IP 上连续错误的次数达到该值后限制尝试的频率
+	twoFactorThrottle         = 10 * time.Second // 限制频率后同一用户两次尝试之间的最小间隔
+	twoFactorEnabledTTL       = 10 * time.Minute // 两步验证启用状态的缓存时间
+)
+
+var (
+	ErrTwoFactorInvalidCode  = errors.New("invalid two-factor authentication code")
+	ErrTwoFactorLoginExpired = errors.New("the two-factor login has expired, please log in again")
+	ErrTwoFactorLocked       = errors.New("too many failed two-factor attempts, please try again later")
+	ErrTwoFactorThrottled    = errors.New("too many failed two-factor attempts, please wait a few seconds and try again")
+)
+
+var twoFactorOpts = totp.ValidateOpts{
+	Period:    twoFactorPeriod,
+	Skew:      twoFactorSkew,
+	Digits:    otp.DigitsSix,
+	Algorithm: otp.AlgorithmSHA1,
+}
+
+// TwoFactorRequired 返回该角色是否被要求开启两步验证
+func (twoFactorService *TwoFactorService) TwoFactorRequired(roleID appTypes.RoleID) bool {
+	return roleID == appTypes.Admin && global.Config.TwoFactor.RequireAdmin
+}
+
+// TwoFactorEnabled 返回用户是否已启用两步验证，结果会缓存在 Redis 中，管理接口的每次请求都会检查
+func (twoFactorService *TwoFactorService) TwoFactorEnabled(userID uint) (bool, error) {
+	key := twoFactorEnabledKey(userID)
+	if value, err := global.Redis.Get(key).Result(); err == nil {
+		return value == "1", nil
+	}
+
+	var count int64
+	if err := global.DB.Model(&database.UserTwoFactor{}).Where("user_id = ? AND enabled = ?", userID, true).Count(&count).Error; err != nil {
+		return false, err
+	}
+	value := "0"
+	if count > 0 {
+		value = "1"
+	}
+	global.Redis.Set(key, value, twoFactorEnabledTTL)
+	return count > 0, nil
+}
+
+// clearTwoFactorEnabled 清除两步验证启用状态的缓存，启用或关闭两步验证的事务提交后调用
+func clearTwoFactorEnabled(userID uint) {
+	global.Redis.Del(twoFactorEnabledKey(userID))
+}
+
+func twoFactorEnabledKey(userID uint) string {
+	return "two_factor_enabled:" + strconv.FormatUint(uint64(userID), 10)
+}
+
+// TwoFactorStatus 获取用户的两步验证状态
+func (twoFactorService *TwoFactorService) TwoFactorStatus(userID uint) (response.TwoFactorStatus, error) {
+	var user database.User
+	if err := global.DB.Select("id", "role_id").Take(&user, userID).Error; err != nil {
+		return response.TwoFactorStatus{}, err
+	}
+	enabled, err := twoFactorService.TwoFactorEnabled(userID)
+	if err != nil {
+		return response.TwoFactorStatus{}, err
+	}
+	var count int64
+	if err := global.DB.Model(&database.UserRecoveryCode{}).Where("user_id = ? AND used = ?", userID, false).Count(&count).Error; err != nil {
+		return response.TwoFactorStatus{}, err
+	}
+	return response.TwoFactorStatus{
+		Enabled:       enabled,
+		Required:      twoFactorService.TwoFactorRequired(user.RoleID),
+		RecoveryCodes: count,
+	}, nil
+}
+
+// TwoFactorEnroll 生成新的 TOTP 密钥，需要通过 TwoFactorConfirm 确认后才会启用
+func (twoFactorService *TwoFactorService) TwoFactorEnroll(userID uint) (response.TwoFactorEnroll, error) {
+	var user database.User
+	if err := global.DB.Take(&user, userID).Error; err != nil {
+		return response.TwoFactorEnroll{}, err
+	}
+
+	var record database.UserTwoFactor
+	err := global.DB.Where("user_id = ?", userID).First(&record).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return response.TwoFactorEnroll{}, err
+	}
+	if record.Enabled {
+		return response.TwoFactorEnroll{}, errors.New("two-factor authentication is already enabled")
+	}
+
+	accountName := user.Email
+	if accountName == "" {
+		accountName = user.Username
+	}
+	key, err := totp.Generate(totp.GenerateOpts{
+		Issuer:      global.Config.TwoFactor.IssuerName(global.Config.Website),
+		AccountName: accountName,
+		Period:      twoFactorPeriod,
+		Digits:      otp.DigitsSix,
+		Algorithm:   otp.AlgorithmSHA1,
+	})
+	if err != nil {
+		return response.TwoFactorEnroll{}, err
+	}
+
+	// 未确认的密钥直接覆盖
+	record.UserID = userID
+	record.Secret = key.Secret()
+	record.LastUsedStep = 0
+	if err := global.DB.Save(&record).Error; err != nil {
+		return response.TwoFactorEnroll{}, err
+	}
+	return response.TwoFactorEnroll{Secret: key.Secret(), URL: key.URL()}, nil
+}
+
+// TwoFactorConfirm 使用身份验证器中的验证码确认并启用两步验证，返回新生成的恢复码
+func (twoFactorService *TwoFactorService) TwoFactorConfirm(req request.TwoFactorCode) ([]string, error) {
+	var record database.UserTwoFactor
+	if err := global.DB.Where("user_id = ?", req.UserID).First(&record).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("two-factor authentication has not been enrolled")
+		}
+		return nil, err
+	}
+	if record.Enabled {
+		return nil, errors.New("two-factor authentication is already enabled")
+	}
+
+	var codes []string
+	err := global.DB.Transaction(func(tx *gorm.DB) error {
+		if err := useTOTP(tx, record, req.Code); err != nil {
+			return err
+		}
+		now := time.Now()
+		if err := tx.Model(&record).Updates(map[string]interface{}{"enabled": true, "enabled_at": &now}).Error; err != nil {
+			return err
+		}
+		var err error
+		codes, err = resetRecoveryCodes(tx, req.UserID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	clearTwoFactorEnabled(req.UserID)
+	return codes, nil
+}
+
+// TwoFactorRecoveryCodes 使用验证码重新生成恢复码，之前的恢复码全部作废
+func (twoFactorService *TwoFactorService) TwoFactorRecoveryCodes(req request.TwoFactorCode) ([]string, error) {
+	record, err := enabledTwoFactor(req.UserID)
+	if err != nil {
+		return nil, err
+	}
+	var codes []string
+	err = global.DB.Transaction(func(tx *gorm.DB) error {
+		if err := useTOTP(tx, record, req.Code); err != nil {
+			return err
+		}
+		var err error
+		codes, err = resetRecoveryCodes(tx, req.UserID)
+		return err
+	})
+	return codes, err
+}
+
+// TwoFactorDisable 使用验证码或恢复码关闭两步验证，被要求开启两步验证的用户无法关闭
+func (twoFactorService *TwoFactorService) TwoFactorDisable(req request.TwoFactorCode) error {
+	var user database.User
+	if err := global.DB.Select("id", "role_id").Take(&user, req.UserID).Error; err != nil {
+		return err
+	}
+	if twoFactorService.TwoFactorRequired(user.RoleID) {
+		return errors.New("two-factor authentication is required for administrators")
+	}
+	record, err := enabledTwoFactor(req.UserID)
+	if err != nil {
+		return err
+	}
+	if err := global.DB.Transaction(func(tx *gorm.DB) error {
+		if err := useTwoFactorCode(tx, record, req.Code); err != nil {
+			return err
+		}
+		return deleteTwoFactor(tx, req.UserID)
+	}); err != nil {
+		return err
+	}
+	clearTwoFactorEnabled(req.UserID)
+	return nil
+}
+
+// TwoFactorChallenge 在密码验证通过后创建第二步登录，返回用于提交验证码的临时令牌
+func (twoFactorService *TwoFactorService) TwoFactorChallenge(userID uint) (response.TwoFactorChallenge, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return response.TwoFactorChallenge{}, err
+	}
+	token := hex.EncodeToString(b)
+	key := "two_factor_login:" + token
+	_, err := global.Redis.TxPipelined(func(pipe redis.Pipeliner) error {
+		pipe.HMSet(key, map[string]interface{}{"user_id": userID, "attempts": 0})
+		pipe.Expire(key, twoFactorLoginTTL)
+		return nil
+	})
+	if err != nil {
+		return response.TwoFactorChallenge{}, err
+	}
+	return response.TwoFactorChallenge{
+		TwoFactorRequired: true,
+		TwoFactorToken:    token,
+		ExpiresAt:         time.Now().Add(twoFactorLoginTTL).Unix() * 1000,
+	}, nil
+}
+
+// TwoFactorLogin 校验第二步登录的验证码或恢复码，成功后返回对应的用户，临时令牌只能使用一次
+func (twoFactorService *TwoFactorService) TwoFactorLogin(req request.TwoFactorLogin) (database.User, error) {
+	key := "two_factor_login:" + req.Token
+	value, err := global.Redis.HGet(key, "user_id").Result()
+	if errors.Is(err, redis.Nil) {
+		return database.User{}, ErrTwoFactorLoginExpired
+	}
+	if err != nil {
+		return database.User{}, err
+	}
+	userID, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return database.User{}, err
+	}
+
+	// 先计数再校验，避免并发请求绕过限制
+	// 锁定只针对出错的 IP，避免攻击者通过不断出错将用户本人锁在外面，所有 IP 上的错误只用于限制尝试的频率
+	ipFailuresKey := "two_factor_failures:" + value + ":" + req.IP
+	userFailuresKey := "two_factor_failures:" + value
+	ipFailures, err := countTwoFactorFailure(ipFailuresKey)
+	if err != nil {
+		return database.User{}, err
+	}
+	if ipFailures > twoFactorMaxFailures {
+		if ipFailures == twoFactorMaxFailures+1 {
+			global.Log.Warn("Security event: two-factor login locked for an IP after too many failed attempts",
+				zap.String("user_id", value),
+				zap.String("ip", req.IP))
+		}
+		return database.User{}, ErrTwoFactorLocked
+	}
+	userFailures, err := countTwoFactorFailure(userFailuresKey)
+	if err != nil {
+		return database.User{}, err
+	}
+	if userFailures > twoFactorUserMaxFailures {
+		if userFailures == twoFactorUserMaxFailures+1 {
+			global.Log.Warn("Security event: two-factor login throttled after too many failed attempts from multiple IPs",
+				zap.String("user_id", value),
+				zap.String("ip", req.IP))
+		}
+		allowed, err := global.Redis.SetNX("two_factor_throttle:"+value, 1, twoFactorThrottle).Result()
+		if err != nil {
+			return database.User{}, err
+		}
+		if !allowed {
+			return database.User{}, ErrTwoFactorThrottled
+		}
+	}
+
+	record, err := enabledTwoFactor(uint(userID))
+	if err != nil {
+		return database.User{}, err
+	}
+	err = global.DB.Transaction(func(tx *gorm.DB) error {
+		return useTwoFactorCode(tx, record, req.Code)
+	})
+	if errors.Is(err, ErrTwoFactorInvalidCode) {
+		// 错误次数达到上限后作废本次登录
+		attempts, incrErr := global.Redis.HIncrBy(key, "attempts", 1).Result()
+		if incrErr == nil && attempts >= twoFactorLoginMaxAttempts {
+			global.Redis.Del(key)
+		}
+		return database.User{}, err
+	}
+	if err != nil {
+		return database.User{}, err
+	}
+
+	// 同一个临时令牌被并发使用时只有一个请求能够成功
+	deleted, err := global.Redis.Del(key).Result()
+	if err != nil {
+		return database.User{}, err
+	}
+	if deleted == 0 {
+		return database.User{}, ErrTwoFactorLoginExpired
+	}
+	global.Redis.Del(ipFailuresKey, userFailuresKey)
+
+	var user database.User
+	if err := global.DB.Take(&user, userID).Error; err != nil {
+		return database.User{}, err
+	}
+	return user, nil
+}
+
+// countTwoFactorFailure 增加第二步登录的错误计数并返回计数值，计数在最后一次错误的 twoFactorLockout 之后过期
+func countTwoFactorFailure(key string) (int64, error) {
+	failures, err := global.Redis.Incr(key).Result()
+	if err != nil {
+		return 0, err
+	}
+	global.Redis.Expire(key, twoFactorLockout)
+	return failures, nil
+}
+
+// enabledTwoFactor 获取用户已启用的两步验证记录
+func enabledTwoFactor(userID uint) (database.UserTwoFactor, error) {
+	var record database.UserTwoFactor
+	err := global.DB.Where("user_id = ? AND enabled = ?", userID, true).First(&record).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return record, errors.New("two-factor authentication is not enabled")
+	}
+	return record, err
+}
+
+// deleteTwoFactor 删除用户的两步验证记录和恢复码
+func deleteTwoFactor(tx *gorm.DB, userID uint) error {
+	if err := tx.Unscoped().Where("user_id = ?", userID).Delete(&database.UserRecoveryCode{}).Error; err != nil {
+		return err
+	}
+	return tx.Unscoped().Where("user_id = ?", userID).Delete(&database.UserTwoFactor{}).Error
+}
+
+// useTwoFactorCode 校验身份验证器中的验证码或一个恢复码，校验通过后验证码或恢复码无法再次使用
+func useTwoFactorCode(tx *gorm.DB, record database.UserTwoFactor, code string) error {
+	code = strings.TrimSpace(code)
+	if len(code) == int(otp.DigitsSix) {
+		return useTOTP(tx, record, code)
+	}
+	return useRecoveryCode(tx, record.UserID, code)
+}
+
+// useTOTP 校验 TOTP 验证码，同一时间步的验证码只能使用一次
+func useTOTP(tx *gorm.DB, record database.UserTwoFactor, code string) error {
+	now := time.Now()
+	for i := -twoFactorSkew; i <= twoFactorSkew; i++ {
+		t := now.Add(time.Duration(i*twoFactorPeriod) * time.Second)
+		expected, err := totp.GenerateCodeCustom(record.Secret, t, twoFactorOpts)
+		if err != nil {
+			return err
+		}
+		if subtle.ConstantTimeCompare([]byte(expected), []byte(code)) != 1 {
+			continue
+		}
+		step := uint64(t.Unix()) / twoFactorPeriod
+		result := tx.Model(&database.UserTwoFactor{}).
+			Where("id = ? AND last_used_step < ?", record.ID, step).
+			Update("last_used_step", step)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrTwoFactorInvalidCode
+		}
+		return nil
+	}
+	return ErrTwoFactorInvalidCode
+}
+
+// useRecoveryCode 使用一个恢复码
+func useRecoveryCode(tx *gorm.DB, userID uint, code string) error {
+	result := tx.Model(&database.UserRecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used = ?", userID, recoveryCodeHash(code), false).
+		Update("used", true)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrTwoFactorInvalidCode
+	}
+	return nil
+}
+
+// resetRecoveryCodes 删除旧的恢复码并生成新的恢复码，只保存恢复码的哈希值
+func resetRecoveryCodes(tx *gorm.DB, userID uint) ([]string, error) {
+	if err := tx.Unscoped().Where("user_id = ?", userID).Delete(&database.UserRecoveryCode{}).Error; err != nil {
+		return nil, err
+	}
+	codes := make([]string, twoFactorRecoveryCodes)
+	records := make([]database.UserRecoveryCode, twoFactorRecoveryCodes)
+	for i := range codes {
+		b := make([]byte, 10)
+		if _, err := rand.Read(b); err != nil {
+			return nil, err
+		}
+		// 16 个字符分为两组，例如 abcd2efg-hijk3mno
+		code := strings.ToLower(base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(b))
+		codes[i] = code[:8] + "-" + code[8:]
+		records[i] = database.UserRecoveryCode{UserID: userID, CodeHash: recoveryCodeHash(codes[i])}
+	}
+	if err := tx.Create(&records).Error; err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// recoveryCodeHash 计算恢复码的哈希值，忽略大小写、空格和连字符
+func recoveryCodeHash(code string) string {
+	code = strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+	sum := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(sum[:])
+}
@@ -86,7 +86,11 @@ func (userService *UserService) ForgotPassword(req request.ForgotPassword) error
 		return err
 	}
 	user.Password = utils.BcryptHash(req.NewPassword)
-	return global.DB.Save(&user).Error
+	if err := global.DB.Save(&user).Error; err != nil {
+		return err
+	}
+	// 找回密码后注销所有会话
+	return ServiceGroupApp.SessionService.SessionRevokeAll(user.ID, "")
 }
 
 func (userService *UserService) UserCard(req request.UserCard) (response.UserCard, error) {
@@ -104,11 +108,10 @@ func (userService *UserService) UserCard(req request.UserCard) (response.UserCar
 }
 
 func (userService *UserService) Logout(c *gin.Context) {
-	uuid := utils.GetUUID(c)
-	jwtStr := utils.GetRefreshToken(c)
+	userID := utils.GetUserID(c)
+	sessionID := utils.GetSessionID(c)
 	utils.ClearRefreshToken(c)
-	global.Redis.Del(uuid.String())
-	_ = ServiceGroupApp.JwtService.JoinInBlacklist(database.JwtBlacklist{Jwt: jwtStr})
+	_ = ServiceGroupApp.SessionService.SessionRevoke(userID, sessionID)
 }
 
 func (userService *UserService) UserResetPassword(req request.UserResetPassword) error {
@@ -120,7 +123,11 @@ func (userService *UserService) UserResetPassword(req request.UserResetPassword)
 		return errors.New("original password does not match the current account")
 	}
 	user.Password = utils.BcryptHash(req.NewPassword)
-	return global.DB.Save(&user).Error
+	if err := global.DB.Save(&user).Error; err != nil {
+		return err
+	}
+	// 修改密码后注销所有会话
+	return ServiceGroupApp.SessionService.SessionRevokeAll(user.ID, "")
 }
 
 func (userService *UserService) UserInfo(userID uint) (database.User, error) {
@@ -214,12 +221,7 @@ func (userService *UserService) UserFreeze(req request.UserOperation) error {
 		return err
 	}
 
-	jwtStr, _ := ServiceGroupApp.JwtService.GetRedisJWT(user.UUID)
-	if jwtStr != "" {
-		_ = ServiceGroupApp.JwtService.JoinInBlacklist(database.JwtBlacklist{Jwt: jwtStr})
-	}
-
-	return nil
+	return ServiceGroupApp.SessionService.SessionRevokeAll(user.ID, "")
 }
 
 func (userService *UserService) UserUnfreeze(req request.UserOperation) error {
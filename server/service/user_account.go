@@ -0,0 +1,145 @@
+package service
+
+import (
+	"errors"
+	"server/global"
+	"server/model/appTypes"
+	"server/model/database"
+	"server/model/request"
+	"server/utils"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// deletedUsername 注销后的用户名
+const deletedUsername = "已注销用户"
+
+// deleteAccountLoginWindow 既没有密码也没有绑定邮箱的用户注销账号时，当前会话必须在该时间内登录
+const deleteAccountLoginWindow = 10 * time.Minute
+
+// ErrRecentLoginRequired 注销账号前需要重新登录
+var ErrRecentLoginRequired = errors.New("please log in again before deleting your account")
+
+// UserChangeEmail 将用户邮箱修改为已通过验证码验证的新邮箱，并通知原邮箱
+func (userService *UserService) UserChangeEmail(req request.UserChangeEmail) error {
+	var user database.User
+	if err := global.DB.Take(&user, req.UserID).Error; err != nil {
+		return err
+	}
+	if user.Email == req.Email {
+		return errors.New("the new email address is the same as the current one")
+	}
+	if !errors.Is(global.DB.Where("email = ? AND id <> ?", req.Email, user.ID).First(&database.User{}).Error, gorm.ErrRecordNotFound) {
+		return errors.New("this email address is already registered")
+	}
+
+	oldEmail := user.Email
+	if err := global.DB.Model(&user).Update("email", req.Email).Error; err != nil {
+		return err
+	}
+
+	// QQ 登录的用户可能没有绑定过邮箱
+	if oldEmail == "" {
+		return nil
+	}
+	subject := "您的邮箱已更换"
+	body := `亲爱的用户[` + user.Username + `]，<br/>
+<br/>
+您在` + global.Config.Website.Name + `的个人博客绑定的邮箱已由 ` + oldEmail + ` 更换为 ` + req.Email + `，此后请使用新邮箱登录。<br/>
+<br/>
+如果这不是您本人的操作，请立即联系我们的支持团队：<br/>
+邮箱：` + global.Config.Email.From + `<br/>
+<br/>
+祝好，<br/>` +
+		global.Config.Website.Title + `<br/>
+<br/>`
+	if err := utils.Email(oldEmail, subject, body); err != nil {
+		// 邮箱已经更换成功，通知失败只记录日志
+		global.Log.Error("Failed to notify the old email address:", zap.Error(err))
+	}
+	return nil
+}
+
+// UserDeleteAccount 注销账号，匿名化用户信息，保留的评论归属于匿名化后的账号，
+// 同时删除收藏、反馈、登录记录和两步验证，并使该用户的所有令牌失效
+func (userService *UserService) UserDeleteAccount(req request.UserDeleteAccount) error {
+	var user database.User
+	if err := global.DB.Take(&user, req.UserID).Error; err != nil {
+		return err
+	}
+	if user.RoleID == appTypes.Admin {
+		return errors.New("administrators cannot delete their own account")
+	}
+	if err := checkDeleteAccountProof(user, req); err != nil {
+		return err
+	}
+
+	err := global.DB.Transaction(func(tx *gorm.DB) error {
+		// 删除收藏，文章收藏数由后台任务同步到 ES
+		var likes []database.ArticleLike
+		if err := tx.Where("user_id = ?", user.ID).Find(&likes).Error; err != nil {
+			return err
+		}
+		for _, like := range likes {
+			if err := tx.Delete(&like).Error; err != nil {
+				return err
+			}
+			if err := enqueueCounter(tx, like.ArticleID, "likes", -1); err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Where("user_uuid = ?", user.UUID).Delete(&database.Feedback{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ?", user.ID).Delete(&database.Login{}).Error; err != nil {
+			return err
+		}
+		if err := deleteTwoFactor(tx, user.ID); err != nil {
+			return err
+		}
+
+		// 匿名化用户信息，保留 uuid 使评论仍能关联到该账号，冻结后无法再登录或刷新令牌
+		return tx.Model(&user).Select("username", "password", "email", "openid", "avatar", "address", "signature", "freeze").
+			Updates(database.User{
+				Username:  deletedUsername,
+				Avatar:    "/image/avatar.jpg",
+				Signature: "该用户已注销。",
+				Freeze:    true,
+			}).Error
+	})
+	if err != nil {
+		return err
+	}
+	clearTwoFactorEnabled(user.ID)
+
+	return ServiceGroupApp.SessionService.SessionRevokeAll(user.ID, "")
+}
+
+// checkDeleteAccountProof 校验注销账号的身份证明，设置了密码的用户校验密码，
+// QQ 登录的用户没有密码，绑定了邮箱时校验邮箱验证码，否则要求当前会话是最近登录的
+func checkDeleteAccountProof(user database.User, req request.UserDeleteAccount) error {
+	if user.Password != "" {
+		if !utils.BcryptCheck(req.Password, user.Password) {
+			return errors.New("incorrect password")
+		}
+		return nil
+	}
+	if user.Email != "" {
+		return ServiceGroupApp.BaseService.VerifyEmailCode(user.Email, appTypes.EmailDeleteAccount, req.VerificationCode)
+	}
+
+	var session database.UserSession
+	if err := global.DB.Where("session_id = ? AND user_id = ?", req.SessionID, user.ID).First(&session).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrRecentLoginRequired
+		}
+		return err
+	}
+	if time.Since(session.CreatedAt) > deleteAccountLoginWindow {
+		return ErrRecentLoginRequired
+	}
+	return nil
+}
@@ -0,0 +1,9 @@
+package task
+
+import "server/service"
+
+// SyncArticleCountersSyncTask 将 outbox 中的文章评论数和收藏数变更同步到 Elasticsearch
+func SyncArticleCountersSyncTask() error {
+	_, err := service.ServiceGroupApp.ArticleService.SyncCounters()
+	return err
+}
@@ -0,0 +1,45 @@
+package task
+
+import (
+	"fmt"
+	"server/global"
+	"server/model/appTypes"
+	"server/model/elasticsearch"
+	"server/service"
+	"time"
+
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
+	"go.uber.org/zap"
+)
+
+// PublishScheduledArticlesSyncTask 将已到发布时间的定时发布文章修改为已发布，单篇文章发布失败时继续发布其余文章
+func PublishScheduledArticlesSyncTask() error {
+	articleService := service.ServiceGroupApp.ArticleService
+
+	// 查找所有发布时间不晚于当前时间的定时发布文章
+	now := time.Now().Format("2006-01-02 15:04:05")
+	query := &types.Query{
+		Bool: &types.BoolQuery{
+			Filter: []types.Query{
+				{Term: map[string]types.TermQuery{"status": {Value: appTypes.Scheduled.String()}}},
+				{Range: map[string]types.RangeQuery{"publish_at": types.DateRangeQuery{Lte: &now}}},
+			},
+		},
+	}
+
+	failed := 0
+	err := articleService.Scan(query, nil, func(id string, article elasticsearch.Article) error {
+		if err := articleService.ArticlePublishScheduled(id, article); err != nil {
+			global.Log.Error("Failed to publish scheduled article:", zap.String("id", id), zap.Error(err))
+			failed++
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if failed > 0 {
+		return fmt.Errorf("failed to publish %d scheduled articles", failed)
+	}
+	return nil
+}
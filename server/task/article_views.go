@@ -2,35 +2,82 @@ package task
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
 	"server/global"
 	"server/model/elasticsearch"
 	"server/service"
 	"strconv"
 
+	"github.com/elastic/go-elasticsearch/v8/typedapi/core/bulk"
 	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
 	"github.com/elastic/go-elasticsearch/v8/typedapi/types/enums/scriptlanguage"
 )
 
-// UpdateArticleViewsSyncTask 将 Redis 中的文章浏览量（增量），同步到 Elasticsearch
+// UpdateArticleViewsSyncTask 将 Redis 中的文章浏览量（增量），批量同步到 Elasticsearch
+// 只扣减已同步的增量，同步期间新增的浏览量会保留到下次同步
 func UpdateArticleViewsSyncTask() error {
 	// 获取redis中的缓存数据
 	articleView := service.ServiceGroupApp.ArticleService.NewArticleView()
 
-	viewsInfo := articleView.GetInfo()
-	for id, num := range viewsInfo {
+	var ids []string
+	var nums []int
+	var request bulk.Request
+	for id, num := range articleView.GetInfo() {
 		// 无变化就跳过
-		if num == 0 {
+		if num <= 0 {
 			continue
 		}
 
 		// 更新数据 之前的数据+缓存中的数据
-		source := "ctx._source.views += " + strconv.Itoa(num)
-		script := types.Script{Source: &source, Lang: &scriptlanguage.Painless}
-		_, err := global.ESClient.Update(elasticsearch.ArticleIndex(), id).Script(&script).Do(context.TODO())
+		articleID := id
+		source := "ctx._source.views += params.views"
+		script := types.Script{
+			Source: &source,
+			Lang:   &scriptlanguage.Painless,
+			Params: map[string]json.RawMessage{"views": json.RawMessage(strconv.Itoa(num))},
+		}
+		request = append(request, types.OperationContainer{Update: &types.UpdateOperation{Id_: &articleID}}, types.UpdateAction{Script: &script})
+		ids = append(ids, id)
+		nums = append(nums, num)
+
+		if len(ids) >= global.Config.ES.Batch() {
+			if err := flushArticleViews(articleView, &request, &ids, &nums); err != nil {
+				return err
+			}
+		}
+	}
+	return flushArticleViews(articleView, &request, &ids, &nums)
+}
+
+// flushArticleViews 执行批量更新，并从 Redis 中扣减同步成功的浏览量
+func flushArticleViews(articleView service.CountDB, request *bulk.Request, ids *[]string, nums *[]int) error {
+	if len(*ids) == 0 {
+		return nil
+	}
+	res, err := global.ESClient.Bulk().Index(elasticsearch.ArticleIndex()).Request(request).Do(context.TODO())
+	if err != nil {
 		return err
 	}
 
-	// 清除redis中的数据
-	articleView.Clear()
+	failed := 0
+	for i, item := range res.Items {
+		for _, result := range item {
+			// 文章已被删除时同样丢弃对应的浏览量
+			if result.Error != nil && result.Status != http.StatusNotFound {
+				failed++
+				continue
+			}
+			if err := articleView.Subtract((*ids)[i], (*nums)[i]); err != nil {
+				return err
+			}
+		}
+	}
+
+	*request, *ids, *nums = nil, nil, nil
+	if failed > 0 {
+		return fmt.Errorf("failed to update the views of %d articles", failed)
+	}
 	return nil
 }
@@ -0,0 +1,27 @@
+package task
+
+import (
+	"fmt"
+	"server/global"
+	"server/service"
+)
+
+// BackupSyncTask 自动备份 MySQL 数据和 ES 文章，并按照保留策略删除过期的备份
+func BackupSyncTask() error {
+	backupService := service.ServiceGroupApp.BackupService
+
+	name, err := backupService.BackupCreate()
+	if err != nil {
+		return err
+	}
+	global.Log.Info("Successfully created backup " + name)
+
+	deleted, err := backupService.BackupPrune()
+	if err != nil {
+		return err
+	}
+	if deleted > 0 {
+		global.Log.Info(fmt.Sprintf("Deleted %d expired backups", deleted))
+	}
+	return nil
+}
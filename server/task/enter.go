@@ -15,6 +15,20 @@ func RegisterScheduledTasks(c *cron.Cron) error {
 	}); err != nil {
 		return err
 	}
+	if _, err := c.AddFunc("@every 1m", func() {
+		if err := PublishScheduledArticlesSyncTask(); err != nil {
+			global.Log.Error("Failed to publish scheduled articles:", zap.Error(err))
+		}
+	}); err != nil {
+		return err
+	}
+	if _, err := c.AddFunc("@every 10s", func() {
+		if err := SyncArticleCountersSyncTask(); err != nil {
+			global.Log.Error("Failed to sync article counters:", zap.Error(err))
+		}
+	}); err != nil {
+		return err
+	}
 	if _, err := c.AddFunc("@hourly", func() {
 		if err := GetHotListSyncTask(); err != nil {
 			global.Log.Error("Failed to get hot list:", zap.Error(err))
@@ -29,5 +43,21 @@ func RegisterScheduledTasks(c *cron.Cron) error {
 	}); err != nil {
 		return err
 	}
+	if _, err := c.AddFunc("@daily", func() {
+		if err := CleanupSessionsSyncTask(); err != nil {
+			global.Log.Error("Failed to clean up sessions:", zap.Error(err))
+		}
+	}); err != nil {
+		return err
+	}
+	if global.Config.Backup.Enable {
+		if _, err := c.AddFunc(global.Config.Backup.Schedule(), func() {
+			if err := BackupSyncTask(); err != nil {
+				global.Log.Error("Failed to back up data:", zap.Error(err))
+			}
+		}); err != nil {
+			return err
+		}
+	}
 	return nil
 }
@@ -0,0 +1,19 @@
+package task
+
+import (
+	"fmt"
+	"server/global"
+	"server/service"
+)
+
+// CleanupSessionsSyncTask 删除过期或已注销的登录会话
+func CleanupSessionsSyncTask() error {
+	deleted, err := service.ServiceGroupApp.SessionService.SessionCleanup()
+	if err != nil {
+		return err
+	}
+	if deleted > 0 {
+		global.Log.Info(fmt.Sprintf("Deleted %d expired sessions", deleted))
+	}
+	return nil
+}
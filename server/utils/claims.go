@@ -152,6 +152,20 @@ func GetUUID(c *gin.Context) uuid.UUID {
 	}
 }
 
+// GetSessionID 从Gin的Context中获取JWT解析出来的会话ID
+func GetSessionID(c *gin.Context) string {
+	if claims, exists := c.Get("claims"); !exists {
+		if cl, err := GetClaims(c); err != nil {
+			return ""
+		} else {
+			return cl.SessionID
+		}
+	} else {
+		waitUse := claims.(*request.JwtCustomClaims)
+		return waitUse.SessionID
+	}
+}
+
 // GetRoleID 从Gin的Context中获取JWT解析出来的用户角色ID
 func GetRoleID(c *gin.Context) appTypes.RoleID {
 	// 首先尝试从Context中获取"claims"
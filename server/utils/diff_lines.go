@@ -0,0 +1,139 @@
+package utils
+
+import (
+	"server/model/other"
+	"strings"
+)
+
+// diffMaxEdits Myers 差分算法允许的最大编辑距离，回溯记录占用的内存与编辑距离的平方成正比，
+// 超过后不再计算最短编辑路径，改为删除所有旧行再插入所有新行
+const diffMaxEdits = 1000
+
+// DiffLines 逐行比较新旧文本，返回按顺序排列的比较结果，相同的开头和结尾不参与差分计算
+func DiffLines(oldText, newText string) []other.DiffLine {
+	a := strings.Split(oldText, "\n")
+	b := strings.Split(newText, "\n")
+
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	lines := make([]other.DiffLine, 0, len(a)+len(b)-prefix-suffix)
+	for i := 1; i <= prefix; i++ {
+		lines = append(lines, other.DiffLine{Type: "equal", OldLine: i, NewLine: i, Content: a[i-1]})
+	}
+
+	oldMiddle, newMiddle := a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+	middle, ok := myersDiff(oldMiddle, newMiddle)
+	if !ok {
+		middle = middle[:0]
+		for i, line := range oldMiddle {
+			middle = append(middle, other.DiffLine{Type: "delete", OldLine: i + 1, Content: line})
+		}
+		for i, line := range newMiddle {
+			middle = append(middle, other.DiffLine{Type: "insert", NewLine: i + 1, Content: line})
+		}
+	}
+	for _, line := range middle {
+		if line.OldLine > 0 {
+			line.OldLine += prefix
+		}
+		if line.NewLine > 0 {
+			line.NewLine += prefix
+		}
+		lines = append(lines, line)
+	}
+
+	for i := suffix; i > 0; i-- {
+		lines = append(lines, other.DiffLine{Type: "equal", OldLine: len(a) - i + 1, NewLine: len(b) - i + 1, Content: a[len(a)-i]})
+	}
+	return lines
+}
+
+// myersDiff 使用 Myers 差分算法逐行比较，编辑距离超过 diffMaxEdits 时返回 false
+func myersDiff(a, b []string) ([]other.DiffLine, bool) {
+	n, m := len(a), len(b)
+
+	// trace 记录每一轮开始前各对角线上能到达的最远 x 坐标，第 d 轮只需要 [-d-1, d+1] 范围内的对角线
+	var trace [][]int
+	v := map[int]int{1: 0}
+	get := func(d, k int) int { return trace[d][k+d+1] }
+
+	for d := 0; d <= n+m; d++ {
+		snapshot := make([]int, 2*d+3)
+		for k := -d - 1; k <= d+1; k++ {
+			snapshot[k+d+1] = v[k]
+		}
+		trace = append(trace, snapshot)
+
+		done := false
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[k-1] < v[k+1]) {
+				x = v[k+1] // 向下移动，即插入一行
+			} else {
+				x = v[k-1] + 1 // 向右移动，即删除一行
+			}
+			y := x - k
+			// 沿对角线尽可能前进，即跳过相同的行
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[k] = x
+			if x >= n && y >= m {
+				done = true
+				break
+			}
+		}
+		if done {
+			break
+		}
+		if d >= diffMaxEdits {
+			return nil, false
+		}
+	}
+
+	// 从终点回溯得到编辑路径，结果是倒序的
+	var reversed []other.DiffLine
+	x, y := n, m
+	for d := len(trace) - 1; d > 0; d-- {
+		k := x - y
+		var prevK int
+		if k == -d || (k != d && get(d, k-1) < get(d, k+1)) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevX := get(d, prevK)
+		prevY := prevX - prevK
+
+		for x > prevX && y > prevY {
+			reversed = append(reversed, other.DiffLine{Type: "equal", OldLine: x, NewLine: y, Content: a[x-1]})
+			x--
+			y--
+		}
+		if x == prevX {
+			reversed = append(reversed, other.DiffLine{Type: "insert", NewLine: y, Content: b[y-1]})
+		} else {
+			reversed = append(reversed, other.DiffLine{Type: "delete", OldLine: x, Content: a[x-1]})
+		}
+		x, y = prevX, prevY
+	}
+	for x > 0 && y > 0 {
+		reversed = append(reversed, other.DiffLine{Type: "equal", OldLine: x, NewLine: y, Content: a[x-1]})
+		x--
+		y--
+	}
+
+	lines := make([]other.DiffLine, len(reversed))
+	for i := range reversed {
+		lines[i] = reversed[len(reversed)-1-i]
+	}
+	return lines, true
+}
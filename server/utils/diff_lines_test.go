@@ -0,0 +1,143 @@
+package utils
+
+import (
+	"fmt"
+	"reflect"
+	"server/model/other"
+	"strings"
+	"testing"
+)
+
+func TestDiffLines(t *testing.T) {
+	tests := []struct {
+		name    string
+		oldText string
+		newText string
+		want    []other.DiffLine
+	}{
+		{
+			name:    "both empty",
+			oldText: "",
+			newText: "",
+			want:    []other.DiffLine{{Type: "equal", OldLine: 1, NewLine: 1, Content: ""}},
+		},
+		{
+			name:    "empty old text",
+			oldText: "",
+			newText: "a\nb",
+			want: []other.DiffLine{
+				{Type: "delete", OldLine: 1, Content: ""},
+				{Type: "insert", NewLine: 1, Content: "a"},
+				{Type: "insert", NewLine: 2, Content: "b"},
+			},
+		},
+		{
+			name:    "identical",
+			oldText: "a\nb\nc",
+			newText: "a\nb\nc",
+			want: []other.DiffLine{
+				{Type: "equal", OldLine: 1, NewLine: 1, Content: "a"},
+				{Type: "equal", OldLine: 2, NewLine: 2, Content: "b"},
+				{Type: "equal", OldLine: 3, NewLine: 3, Content: "c"},
+			},
+		},
+		{
+			name:    "changed middle line",
+			oldText: "a\nb\nc",
+			newText: "a\nx\nc",
+			want: []other.DiffLine{
+				{Type: "equal", OldLine: 1, NewLine: 1, Content: "a"},
+				{Type: "delete", OldLine: 2, Content: "b"},
+				{Type: "insert", NewLine: 2, Content: "x"},
+				{Type: "equal", OldLine: 3, NewLine: 3, Content: "c"},
+			},
+		},
+		{
+			name:    "inserted and deleted lines",
+			oldText: "a\nb\nc\nd",
+			newText: "b\nc\ne\nd",
+			want: []other.DiffLine{
+				{Type: "delete", OldLine: 1, Content: "a"},
+				{Type: "equal", OldLine: 2, NewLine: 1, Content: "b"},
+				{Type: "equal", OldLine: 3, NewLine: 2, Content: "c"},
+				{Type: "insert", NewLine: 3, Content: "e"},
+				{Type: "equal", OldLine: 4, NewLine: 4, Content: "d"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := DiffLines(tt.oldText, tt.newText)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DiffLines(%q, %q) = %+v, want %+v", tt.oldText, tt.newText, got, tt.want)
+			}
+			checkDiffLines(t, tt.oldText, tt.newText, got)
+		})
+	}
+}
+
+func TestDiffLinesFallback(t *testing.T) {
+	// 中间的行全部不同，编辑距离超过 diffMaxEdits，改为删除所有旧行再插入所有新行
+	n := diffMaxEdits
+	oldLines := []string{"head"}
+	newLines := []string{"head"}
+	for i := 0; i < n; i++ {
+		oldLines = append(oldLines, fmt.Sprintf("old %d", i))
+		newLines = append(newLines, fmt.Sprintf("new %d", i))
+	}
+	oldLines = append(oldLines, "tail")
+	newLines = append(newLines, "tail")
+	oldText, newText := strings.Join(oldLines, "\n"), strings.Join(newLines, "\n")
+	if _, ok := myersDiff(oldLines[1:n+1], newLines[1:n+1]); ok {
+		t.Fatalf("myersDiff succeeded with an edit distance of %d, want it to give up after %d", 2*n, diffMaxEdits)
+	}
+
+	got := DiffLines(oldText, newText)
+	if len(got) != 2*n+2 {
+		t.Fatalf("DiffLines returned %d lines, want %d", len(got), 2*n+2)
+	}
+	for i, line := range got {
+		var want string
+		switch {
+		case i == 0 || i == len(got)-1:
+			want = "equal"
+		case i <= n:
+			want = "delete"
+		default:
+			want = "insert"
+		}
+		if line.Type != want {
+			t.Fatalf("line %d has type %q, want %q", i, line.Type, want)
+		}
+	}
+	if got[1].OldLine != 2 || got[n+1].NewLine != 2 {
+		t.Errorf("fallback lines are not offset by the common prefix: %+v, %+v", got[1], got[n+1])
+	}
+	checkDiffLines(t, oldText, newText, got)
+}
+
+// checkDiffLines 检查比较结果能够还原新旧文本，并且行号连续
+func checkDiffLines(t *testing.T, oldText, newText string, lines []other.DiffLine) {
+	t.Helper()
+	var oldLines, newLines []string
+	for _, line := range lines {
+		if line.Type != "insert" {
+			oldLines = append(oldLines, line.Content)
+			if line.OldLine != len(oldLines) {
+				t.Fatalf("line %+v has old line number %d, want %d", line, line.OldLine, len(oldLines))
+			}
+		}
+		if line.Type != "delete" {
+			newLines = append(newLines, line.Content)
+			if line.NewLine != len(newLines) {
+				t.Fatalf("line %+v has new line number %d, want %d", line, line.NewLine, len(newLines))
+			}
+		}
+	}
+	if got := strings.Join(oldLines, "\n"); got != oldText {
+		t.Errorf("old text rebuilt from the diff is %q, want %q", got, oldText)
+	}
+	if got := strings.Join(newLines, "\n"); got != newText {
+		t.Errorf("new text rebuilt from the diff is %q, want %q", got, newText)
+	}
+}
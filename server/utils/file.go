@@ -0,0 +1,25 @@
+package utils
+
+import (
+	"bufio"
+	"io"
+	"os"
+)
+
+// WriteFile 创建文件并通过 write 写入内容
+func WriteFile(name string, write func(w io.Writer) error) error {
+	file, err := os.Create(name)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	bw := bufio.NewWriter(file)
+	if err := write(bw); err != nil {
+		return err
+	}
+	if err := bw.Flush(); err != nil {
+		return err
+	}
+	return file.Close()
+}
@@ -0,0 +1,53 @@
+package utils
+
+import (
+	"bytes"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const frontMatterDelimiter = "---"
+
+// ParseFrontMatter 解析 Markdown 文件开头的 YAML front matter，返回去掉 front matter 后的正文
+// 如果文件没有 front matter，则正文为整个文件
+func ParseFrontMatter(data []byte, v any) (string, error) {
+	content := strings.TrimPrefix(string(data), "\ufeff")
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+
+	lines := strings.Split(content, "\n")
+	if strings.TrimSpace(lines[0]) != frontMatterDelimiter {
+		return content, nil
+	}
+	for i := 1; i < len(lines); i++ {
+		if strings.TrimSpace(lines[i]) != frontMatterDelimiter {
+			continue
+		}
+		if err := yaml.Unmarshal([]byte(strings.Join(lines[1:i], "\n")), v); err != nil {
+			return "", err
+		}
+		return strings.TrimLeft(strings.Join(lines[i+1:], "\n"), "\n"), nil
+	}
+	// 没有找到结束分隔符，视为没有 front matter
+	return content, nil
+}
+
+// FormatFrontMatter 生成带有 YAML front matter 的 Markdown 文件
+func FormatFrontMatter(v any, body string) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString(frontMatterDelimiter + "\n")
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(v); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	buf.WriteString(frontMatterDelimiter + "\n\n")
+	buf.WriteString(body)
+	if !strings.HasSuffix(body, "\n") {
+		buf.WriteString("\n")
+	}
+	return buf.Bytes(), nil
+}
@@ -6,6 +6,7 @@ import (
 	"server/model/request"
 	"time"
 
+	"github.com/gofrs/uuid"
 	"github.com/golang-jwt/jwt/v4"
 )
 
@@ -52,8 +53,10 @@ func (j *JWT) CreateAccessToken(claims request.JwtCustomClaims) (string, error)
 func (j *JWT) CreateRefreshClaims(baseClaims request.BaseClaims) request.JwtCustomRefreshClaims {
 	ep, _ := ParseDuration(global.Config.Jwt.RefreshTokenExpiryTime) // 获取过期时间
 	claims := request.JwtCustomRefreshClaims{
-		UserID: baseClaims.UserID, // 用户 ID
+		UserID:    baseClaims.UserID,    // 用户 ID
+		SessionID: baseClaims.SessionID, // 会话 ID
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.Must(uuid.NewV4()).String(),       // 令牌 ID，保证每次签发的刷新令牌都不相同
 			Audience:  jwt.ClaimStrings{"TAP"},                // 受众
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(ep)), // 过期时间
 			Issuer:    global.Config.Jwt.Issuer,               // 签名的发行者
@@ -62,6 +65,13 @@ func (j *JWT) CreateRefreshClaims(baseClaims request.BaseClaims) request.JwtCust
 	return claims
 }
 
+// RotateRefreshClaims 基于旧的 Refresh Token Claims 创建轮换后的 Claims，过期时间保持不变
+func (j *JWT) RotateRefreshClaims(claims request.JwtCustomRefreshClaims) request.JwtCustomRefreshClaims {
+	claims.ID = uuid.Must(uuid.NewV4()).String()
+	claims.IssuedAt = jwt.NewNumericDate(time.Now())
+	return claims
+}
+
 // CreateRefreshToken 创建 Refresh Token，通过 Claims 生成 JWT Token
 func (j *JWT) CreateRefreshToken(claims request.JwtCustomRefreshClaims) (string, error) {
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims) // 创建新的 JWT Token
@@ -0,0 +1,159 @@
+package utils
+
+import (
+	"bytes"
+	"math"
+	"regexp"
+	"server/model/other"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/text"
+)
+
+const (
+	cjkCharsPerMinute = 300 // 中日韩文字每分钟的阅读字数
+	wordsPerMinute    = 200 // 其他文字每分钟的阅读单词数
+)
+
+var markdown = goldmark.New(
+	goldmark.WithExtensions(extension.GFM),
+	goldmark.WithParserOptions(parser.WithAutoHeadingID()),
+	// 允许原始 HTML，输出后统一经过安全过滤
+	goldmark.WithRendererOptions(html.WithUnsafe()),
+)
+
+var markdownPolicy = func() *bluemonday.Policy {
+	policy := bluemonday.UGCPolicy()
+	policy.AllowAttrs("id").OnElements("h1", "h2", "h3", "h4", "h5", "h6")
+	policy.AllowAttrs("class").Matching(regexp.MustCompile(`^language-[\w+#-]+$`)).OnElements("code")
+	policy.AllowAttrs("type").Matching(regexp.MustCompile(`^checkbox$`)).OnElements("input")
+	policy.AllowAttrs("checked", "disabled").OnElements("input")
+	return policy
+}()
+
+// RenderMarkdown 将 Markdown 渲染为安全的 HTML，同时生成目录、字数和预计阅读时间
+func RenderMarkdown(content string) (other.MarkdownRender, error) {
+	source := []byte(content)
+	ctx := parser.NewContext(parser.WithIDs(newHeadingIDs()))
+	doc := markdown.Parser().Parse(text.NewReader(source), parser.WithContext(ctx))
+
+	result := other.MarkdownRender{Toc: []other.TocItem{}}
+	var plain strings.Builder
+	err := ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch node := n.(type) {
+		case *ast.Heading:
+			id, _ := node.AttributeString("id")
+			idBytes, _ := id.([]byte)
+			result.Toc = append(result.Toc, other.TocItem{
+				Level: node.Level,
+				Text:  nodeText(node, source),
+				ID:    string(idBytes),
+			})
+		case *ast.FencedCodeBlock, *ast.CodeBlock, *ast.HTMLBlock, *ast.RawHTML:
+			// 代码块和 HTML 不计入字数
+			return ast.WalkSkipChildren, nil
+		case *ast.Text:
+			plain.Write(node.Segment.Value(source))
+			plain.WriteByte(' ')
+		case *ast.String:
+			plain.Write(node.Value)
+			plain.WriteByte(' ')
+		}
+		return ast.WalkContinue, nil
+	})
+	if err != nil {
+		return other.MarkdownRender{}, err
+	}
+
+	var buf bytes.Buffer
+	if err := markdown.Renderer().Render(&buf, source, doc); err != nil {
+		return other.MarkdownRender{}, err
+	}
+	result.HTML = markdownPolicy.Sanitize(buf.String())
+
+	cjk, words := CountWords(plain.String())
+	result.WordCount = cjk + words
+	if result.WordCount > 0 {
+		result.ReadingTime = int(math.Ceil(float64(cjk)/cjkCharsPerMinute + float64(words)/wordsPerMinute))
+	}
+	return result, nil
+}
+
+// CountWords 统计文本的字数，分别返回中日韩文字的字数和其他文字的单词数
+func CountWords(s string) (cjk int, words int) {
+	inWord := false
+	for _, r := range s {
+		switch {
+		case unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul):
+			cjk++
+			inWord = false
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '\'' && inWord:
+			if !inWord {
+				words++
+				inWord = true
+			}
+		default:
+			inWord = false
+		}
+	}
+	return cjk, words
+}
+
+// nodeText 返回节点中的纯文本，包括行内代码等子节点中的文本
+func nodeText(n ast.Node, source []byte) string {
+	var buf bytes.Buffer
+	_ = ast.Walk(n, func(child ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch node := child.(type) {
+		case *ast.Text:
+			buf.Write(node.Segment.Value(source))
+			if node.SoftLineBreak() {
+				buf.WriteByte(' ')
+			}
+		case *ast.String:
+			buf.Write(node.Value)
+		}
+		return ast.WalkContinue, nil
+	})
+	return buf.String()
+}
+
+// headingIDs 生成标题的锚点 ID，规则与文章 slug 相同，相同内容的标题追加数字后缀
+// 只要标题内容和顺序不变，生成的 ID 就保持不变
+type headingIDs struct {
+	used map[string]bool
+}
+
+func newHeadingIDs() *headingIDs {
+	return &headingIDs{used: make(map[string]bool)}
+}
+
+func (h *headingIDs) Generate(value []byte, kind ast.NodeKind) []byte {
+	base := slugify(string(value))
+	if base == "" {
+		base = "heading"
+	}
+	id := base
+	for i := 1; h.used[id]; i++ {
+		id = base + "-" + strconv.Itoa(i)
+	}
+	h.used[id] = true
+	return []byte(id)
+}
+
+func (h *headingIDs) Put(value []byte) {
+	h.used[string(value)] = true
+}
@@ -0,0 +1,21 @@
+package utils
+
+import (
+	"encoding/json"
+	"io"
+	"server/model/other"
+)
+
+// WriteNDJSON 将 ES 文档逐行写入 NDJSON
+func WriteNDJSON(w io.Writer, batch []other.Data) error {
+	for _, data := range batch {
+		line, err := json.Marshal(data)
+		if err != nil {
+			return err
+		}
+		if _, err := w.Write(append(line, '\n')); err != nil {
+			return err
+		}
+	}
+	return nil
+}
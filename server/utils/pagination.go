@@ -5,6 +5,7 @@ import (
 	"server/global"
 	"server/model/other"
 
+	"github.com/elastic/go-elasticsearch/v8/typedapi/core/search"
 	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
 )
 
@@ -50,6 +51,19 @@ func MySQLPagination[T any](model *T, option other.MySQLOption) (list []T, total
 
 // EsPagination 实现 Elasticsearch 数据分页查询
 func EsPagination(ctx context.Context, option other.EsOption) (list []types.Hit, total int64, err error) {
+	res, err := EsSearch(ctx, option)
+	if err != nil {
+		return nil, 0, err // 如果查询失败，返回错误
+	}
+
+	// 提取查询结果
+	list = res.Hits.Hits         // 获取查询结果中的文档
+	total = res.Hits.Total.Value // 获取符合条件的文档总数
+	return list, total, nil      // 返回查询结果和总文档数
+}
+
+// EsSearch 执行 Elasticsearch 分页查询并返回完整的响应，可以从中读取聚合结果
+func EsSearch(ctx context.Context, option other.EsOption) (*search.Response, error) {
 	// 设置分页的默认值
 	if option.Page < 1 {
 		option.Page = 1 // 页码不能小于1，默认为1
@@ -64,17 +78,10 @@ func EsPagination(ctx context.Context, option other.EsOption) (list []types.Hit,
 	option.Request.From = &from                 // 设置起始记录位置
 
 	// 执行 Elasticsearch 搜索查询
-	res, err := global.ESClient.Search().
+	return global.ESClient.Search().
 		Index(option.Index).                       // 指定索引
 		Request(option.Request).                   // 应用查询请求
 		SourceIncludes_(option.SourceIncludes...). // 设置需要包含的字段
+		TypedKeys(true).                           // 返回带类型的聚合结果，便于解析
 		Do(ctx)                                    // 执行查询
-	if err != nil {
-		return nil, 0, err // 如果查询失败，返回错误
-	}
-
-	// 提取查询结果
-	list = res.Hits.Hits         // 获取查询结果中的文档
-	total = res.Hits.Total.Value // 获取符合条件的文档总数
-	return list, total, nil      // 返回查询结果和总文档数
 }
@@ -0,0 +1,71 @@
+package utils
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/mozillazg/go-pinyin"
+	"golang.org/x/text/unicode/norm"
+)
+
+// slugMaxLength slug 的最大长度
+const slugMaxLength = 80
+
+// GenerateSlug 根据标题生成 slug，英文和数字转为小写保留，中文转换为不带声调的拼音，其余字符作为分隔符
+// 例如 "Go 语言入门" 会被转换为 "go-yu-yan-ru-men"
+func GenerateSlug(title string) string {
+	if slug := slugify(title); slug != "" {
+		return slug
+	}
+	return "article"
+}
+
+// slugify 生成 slug，没有可用的字符时返回空字符串
+func slugify(title string) string {
+	args := pinyin.NewArgs()
+
+	var words []string
+	var word strings.Builder
+	flush := func() {
+		if word.Len() > 0 {
+			words = append(words, word.String())
+			word.Reset()
+		}
+	}
+
+	// 分解带重音的字母，例如 "é" 分解为 "e" 和重音符号，重音符号随后被忽略
+	for _, r := range norm.NFD.String(title) {
+		switch {
+		case unicode.Is(unicode.Mn, r):
+			continue
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			word.WriteRune(unicode.ToLower(r))
+		case unicode.Is(unicode.Han, r):
+			// 每个汉字的拼音作为一个单独的单词
+			flush()
+			if p := pinyin.SinglePinyin(r, args); len(p) > 0 {
+				words = append(words, p[0])
+			}
+		default:
+			flush()
+		}
+	}
+	flush()
+
+	// 限制长度，并且不在单词中间截断，只有第一个单词就超过长度时才截断该单词
+	var slug string
+	for _, w := range words {
+		if slug == "" {
+			if len(w) > slugMaxLength {
+				return w[:slugMaxLength]
+			}
+			slug = w
+			continue
+		}
+		if len(slug)+len(w)+1 > slugMaxLength {
+			break
+		}
+		slug += "-" + w
+	}
+	return slug
+}
@@ -0,0 +1,36 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGenerateSlug(t *testing.T) {
+	tests := []struct {
+		name  string
+		title string
+		want  string
+	}{
+		{"english", "Hello, World!", "hello-world"},
+		{"cjk", "Go 语言入门", "go-yu-yan-ru-men"},
+		{"cjk only", "你好世界", "ni-hao-shi-jie"},
+		{"accents", "Café Déjà Vu", "cafe-deja-vu"},
+		{"digits", "Go 1.22 发布", "go-1-22-fa-bu"},
+		{"no usable characters", "！？…", "article"},
+		{"empty", "", "article"},
+		{"long title", strings.Repeat("word ", 30), strings.TrimSuffix(strings.Repeat("word-", 16), "-")},
+		{"long first word", strings.Repeat("a", 100) + " tail", strings.Repeat("a", 80)},
+		{"first word at the limit", strings.Repeat("b", 80) + " tail", strings.Repeat("b", 80)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := GenerateSlug(tt.title)
+			if got != tt.want {
+				t.Errorf("GenerateSlug(%q) = %q, want %q", tt.title, got, tt.want)
+			}
+			if len(got) > slugMaxLength {
+				t.Errorf("GenerateSlug(%q) has length %d, want at most %d", tt.title, len(got), slugMaxLength)
+			}
+		})
+	}
+}
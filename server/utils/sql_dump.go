@@ -0,0 +1,323 @@
+package utils
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	sqlInsertRows  = 500     // 每条 INSERT 语句最多包含的行数
+	sqlInsertBytes = 1 << 20 // 每条 INSERT 语句的最大字节数，避免超过 max_allowed_packet
+)
+
+// sqlTablePattern 用于识别语句所操作的表
+var sqlTablePattern = regexp.MustCompile("(?i)^(?:DROP TABLE IF EXISTS|DROP TABLE|CREATE TABLE IF NOT EXISTS|CREATE TABLE|INSERT INTO|REPLACE INTO|LOCK TABLES|ALTER TABLE)\\s+`?([\\w$]+)`?")
+
+// DumpSQL 在一致性快照事务中导出指定表的结构与数据为 SQL 语句
+func DumpSQL(db *sql.DB, w io.Writer, tables []string) error {
+	ctx := context.TODO()
+	// 使用独立连接开启只读的一致性快照事务，保证所有表的数据处于同一时间点
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, "SET SESSION TRANSACTION ISOLATION LEVEL REPEATABLE READ"); err != nil {
+		return err
+	}
+	if _, err := conn.ExecContext(ctx, "START TRANSACTION WITH CONSISTENT SNAPSHOT, READ ONLY"); err != nil {
+		return err
+	}
+	defer func() {
+		_, _ = conn.ExecContext(ctx, "ROLLBACK")
+	}()
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "-- Blog_go SQL dump\n-- Generated at %s\n\n", time.Now().Format("2006-01-02 15:04:05"))
+	fmt.Fprint(bw, "SET NAMES utf8mb4;\nSET FOREIGN_KEY_CHECKS=0;\n\n")
+
+	for _, table := range tables {
+		if err := dumpTable(ctx, conn, bw, table); err != nil {
+			return fmt.Errorf("failed to dump table %s: %w", table, err)
+		}
+	}
+
+	fmt.Fprint(bw, "SET FOREIGN_KEY_CHECKS=1;\n")
+	return bw.Flush()
+}
+
+// dumpTable 导出单张表的结构与数据
+func dumpTable(ctx context.Context, conn *sql.Conn, w *bufio.Writer, table string) error {
+	var name, createSQL string
+	if err := conn.QueryRowContext(ctx, "SHOW CREATE TABLE "+quoteIdentifier(table)).Scan(&name, &createSQL); err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "-- Table structure for %s\nDROP TABLE IF EXISTS %s;\n%s;\n\n", table, quoteIdentifier(table), createSQL)
+
+	rows, err := conn.QueryContext(ctx, "SELECT * FROM "+quoteIdentifier(table))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	columnTypes, err := rows.ColumnTypes()
+	if err != nil {
+		return err
+	}
+	columns := make([]string, len(columnTypes))
+	for i, columnType := range columnTypes {
+		columns[i] = quoteIdentifier(columnType.Name())
+	}
+	prefix := fmt.Sprintf("INSERT INTO %s (%s) VALUES\n", quoteIdentifier(table), strings.Join(columns, ", "))
+
+	fmt.Fprintf(w, "-- Data for %s\n", table)
+	values := make([]any, len(columnTypes))
+	dest := make([]any, len(columnTypes))
+	for i := range values {
+		dest[i] = &values[i]
+	}
+
+	var statement strings.Builder
+	count := 0
+	flush := func() {
+		if count == 0 {
+			return
+		}
+		w.WriteString(prefix)
+		w.WriteString(statement.String())
+		w.WriteString(";\n")
+		statement.Reset()
+		count = 0
+	}
+	for rows.Next() {
+		if err := rows.Scan(dest...); err != nil {
+			return err
+		}
+		if count > 0 {
+			statement.WriteString(",\n")
+		}
+		statement.WriteByte('(')
+		for i, value := range values {
+			if i > 0 {
+				statement.WriteString(", ")
+			}
+			statement.WriteString(sqlLiteral(value, columnTypes[i].DatabaseTypeName()))
+		}
+		statement.WriteByte(')')
+		count++
+		if count >= sqlInsertRows || statement.Len() >= sqlInsertBytes {
+			flush()
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	flush()
+	w.WriteString("\n")
+	return nil
+}
+
+// sqlLiteral 将查询到的值转换为 SQL 字面量
+func sqlLiteral(value any, databaseType string) string {
+	switch v := value.(type) {
+	case nil:
+		return "NULL"
+	case []byte:
+		switch databaseType {
+		case "BINARY", "VARBINARY", "TINYBLOB", "BLOB", "MEDIUMBLOB", "LONGBLOB", "BIT", "GEOMETRY":
+			if len(v) == 0 {
+				return "''"
+			}
+			return "0x" + hex.EncodeToString(v)
+		case "TINYINT", "SMALLINT", "MEDIUMINT", "INT", "BIGINT", "UNSIGNED TINYINT", "UNSIGNED SMALLINT",
+			"UNSIGNED MEDIUMINT", "UNSIGNED INT", "UNSIGNED BIGINT", "DECIMAL", "FLOAT", "DOUBLE", "YEAR":
+			return string(v)
+		}
+		return quoteString(string(v))
+	case time.Time:
+		// 零值按原样输出为 0001-01-01，与 GORM 写入的值一致，且在 NO_ZERO_DATE 模式下也能导入
+		return "'" + v.Format("2006-01-02 15:04:05.999999") + "'"
+	case string:
+		return quoteString(v)
+	case bool:
+		if v {
+			return "1"
+		}
+		return "0"
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// quoteString 转义并引用字符串
+func quoteString(s string) string {
+	var b strings.Builder
+	b.Grow(len(s) + 2)
+	b.WriteByte('\'')
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; c {
+		case 0:
+			b.WriteString(`\0`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\\':
+			b.WriteString(`\\`)
+		case '\'':
+			b.WriteString(`\'`)
+		case '\x1a':
+			b.WriteString(`\Z`)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	b.WriteByte('\'')
+	return b.String()
+}
+
+// quoteIdentifier 引用表名或列名
+func quoteIdentifier(name string) string {
+	return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+}
+
+// RestoreSQL 逐条执行 SQL 语句恢复数据，tables 不为空时只执行与这些表相关的语句
+func RestoreSQL(db *sql.DB, r io.Reader, tables []string) (errs []error) {
+	ctx := context.TODO()
+	// 使用独立连接，保证 SET FOREIGN_KEY_CHECKS 等会话变量对后续语句生效
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return append(errs, err)
+	}
+	defer conn.Close()
+
+	selected := make(map[string]bool, len(tables))
+	for _, table := range tables {
+		selected[strings.ToLower(table)] = true
+	}
+
+	err = SplitSQL(r, func(statement string) error {
+		if len(selected) > 0 {
+			if match := sqlTablePattern.FindStringSubmatch(statement); match != nil && !selected[strings.ToLower(match[1])] {
+				return nil
+			}
+		}
+		if _, err := conn.ExecContext(ctx, statement); err != nil {
+			errs = append(errs, fmt.Errorf("%w: %s", err, truncateSQL(statement)))
+		}
+		return nil
+	})
+	if err != nil {
+		errs = append(errs, err)
+	}
+	return errs
+}
+
+// truncateSQL 截断过长的语句，便于记录错误
+func truncateSQL(statement string) string {
+	if len(statement) > 100 {
+		return statement[:100] + "..."
+	}
+	return statement
+}
+
+// SplitSQL 按分号拆分 SQL 语句，忽略引号、反引号以及注释中的分号
+func SplitSQL(r io.Reader, fn func(statement string) error) error {
+	br := bufio.NewReader(r)
+	var statement strings.Builder
+	var quote byte           // 当前所在的引号，0 表示不在引号中
+	lineComment := false     // 是否在行注释中
+	blockComment := false    // 是否在块注释中
+	executableBlock := false // 是否在 /*! ... */ 可执行注释中，其内容需要保留
+
+	emit := func() error {
+		s := strings.TrimSpace(statement.String())
+		statement.Reset()
+		if s == "" {
+			return nil
+		}
+		return fn(s)
+	}
+
+	for {
+		c, err := br.ReadByte()
+		if err == io.EOF {
+			return emit()
+		}
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case lineComment:
+			if c == '\n' {
+				lineComment = false
+				statement.WriteByte(c)
+			}
+			continue
+		case blockComment:
+			if executableBlock {
+				statement.WriteByte(c)
+			}
+			if c == '*' {
+				if next, _ := br.Peek(1); len(next) == 1 && next[0] == '/' {
+					br.ReadByte()
+					if executableBlock {
+						statement.WriteByte('/')
+					}
+					blockComment, executableBlock = false, false
+				}
+			}
+			continue
+		case quote != 0:
+			statement.WriteByte(c)
+			if c == '\\' && quote != '`' {
+				if next, err := br.ReadByte(); err == nil {
+					statement.WriteByte(next)
+				}
+			} else if c == quote {
+				quote = 0
+			}
+			continue
+		}
+
+		switch c {
+		case '\'', '"', '`':
+			quote = c
+			statement.WriteByte(c)
+		case '#':
+			lineComment = true
+		case '-':
+			if next, _ := br.Peek(2); len(next) == 2 && next[0] == '-' && (next[1] == ' ' || next[1] == '\t' || next[1] == '\n' || next[1] == '\r') {
+				lineComment = true
+			} else {
+				statement.WriteByte(c)
+			}
+		case '/':
+			if next, _ := br.Peek(2); len(next) >= 1 && next[0] == '*' {
+				blockComment = true
+				br.ReadByte()
+				if len(next) == 2 && next[1] == '!' {
+					executableBlock = true
+					statement.WriteString("/*")
+				}
+			} else {
+				statement.WriteByte(c)
+			}
+		case ';':
+			if err := emit(); err != nil {
+				return err
+			}
+		default:
+			statement.WriteByte(c)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package utils
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSplitSQL(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []string
+	}{
+		{
+			name:  "simple statements",
+			input: "SELECT 1;\nSELECT 2;\n",
+			want:  []string{"SELECT 1", "SELECT 2"},
+		},
+		{
+			name:  "last statement without semicolon",
+			input: "SELECT 1;\nSELECT 2",
+			want:  []string{"SELECT 1", "SELECT 2"},
+		},
+		{
+			name:  "empty statements",
+			input: ";;\n  ;",
+			want:  nil,
+		},
+		{
+			name:  "semicolon in single quotes",
+			input: "INSERT INTO t VALUES ('a;b');SELECT 1;",
+			want:  []string{"INSERT INTO t VALUES ('a;b')", "SELECT 1"},
+		},
+		{
+			name:  "semicolon in double quotes",
+			input: `INSERT INTO t VALUES ("a;b");`,
+			want:  []string{`INSERT INTO t VALUES ("a;b")`},
+		},
+		{
+			name:  "semicolon in backquotes",
+			input: "SELECT `a;b` FROM t;",
+			want:  []string{"SELECT `a;b` FROM t"},
+		},
+		{
+			name:  "escaped quote in string",
+			input: `INSERT INTO t VALUES ('it\'s;fine');SELECT 1;`,
+			want:  []string{`INSERT INTO t VALUES ('it\'s;fine')`, "SELECT 1"},
+		},
+		{
+			name:  "doubled quote in string",
+			input: "INSERT INTO t VALUES ('it''s;fine');",
+			want:  []string{"INSERT INTO t VALUES ('it''s;fine')"},
+		},
+		{
+			name:  "double dash comment",
+			input: "-- drop; everything\nSELECT 1;",
+			want:  []string{"SELECT 1"},
+		},
+		{
+			name:  "double dash without space is not a comment",
+			input: "SELECT 1--1;",
+			want:  []string{"SELECT 1--1"},
+		},
+		{
+			name:  "hash comment",
+			input: "# comment; here\nSELECT 1;",
+			want:  []string{"SELECT 1"},
+		},
+		{
+			name:  "block comment",
+			input: "SELECT /* a; b */ 1;",
+			want:  []string{"SELECT  1"},
+		},
+		{
+			name:  "executable comment is kept",
+			input: "/*!40101 SET NAMES utf8mb4 */;\nSELECT 1;",
+			want:  []string{"/*!40101 SET NAMES utf8mb4 */", "SELECT 1"},
+		},
+		{
+			name:  "comment markers in string",
+			input: "INSERT INTO t VALUES ('-- x; /* y; */ # z');",
+			want:  []string{"INSERT INTO t VALUES ('-- x; /* y; */ # z')"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			err := SplitSQL(strings.NewReader(tt.input), func(statement string) error {
+				got = append(got, statement)
+				return nil
+			})
+			if err != nil {
+				t.Fatalf("SplitSQL(%q) returned error: %v", tt.input, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SplitSQL(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,143 @@
+package upload
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"server/global"
+	"server/model/appTypes"
+	"server/model/other"
+	"strings"
+	"time"
+
+	"github.com/qiniu/go-sdk/v7/auth/qbox"
+	"github.com/qiniu/go-sdk/v7/storage"
+)
+
+// BackupStorage 备份存储接口定义，规定了备份文件的保存、列举和删除方法
+type BackupStorage interface {
+	SaveBackup(key, localPath string) error
+	ListBackups() ([]other.BackupObject, error)
+	DeleteBackup(key string) error
+}
+
+// NewBackupStorage 根据配置中的备份存储类型返回相应的备份存储实例
+// 备份中包含密码哈希、邮箱和两步验证密钥等敏感数据，使用七牛云存储时必须配置单独的私有空间
+func NewBackupStorage() (BackupStorage, error) {
+	switch global.Config.Backup.Storage() {
+	case appTypes.Qiniu:
+		bucket := global.Config.Backup.Bucket
+		if bucket == "" {
+			return nil, errors.New("backup.bucket must be set to a private bucket to store backups in qiniu")
+		}
+		if bucket == global.Config.Qiniu.Bucket {
+			return nil, errors.New("backup.bucket must not be the public image bucket")
+		}
+		return &QiniuBackup{}, nil
+	default:
+		return &LocalBackup{}, nil
+	}
+}
+
+// LocalBackup 将备份保存在本地备份目录中
+type LocalBackup struct{}
+
+func (*LocalBackup) SaveBackup(key, localPath string) error {
+	target := filepath.Join(global.Config.Backup.Dir(), filepath.FromSlash(key))
+	if err := os.MkdirAll(filepath.Dir(target), os.ModePerm); err != nil {
+		return err
+	}
+	return os.Rename(localPath, target)
+}
+
+func (*LocalBackup) ListBackups() ([]other.BackupObject, error) {
+	root := global.Config.Backup.Dir()
+	var objects []other.BackupObject
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if errors.Is(err, fs.ErrNotExist) && path == root {
+			return filepath.SkipDir
+		}
+		if err != nil {
+			return err
+		}
+		// 跳过正在生成的备份
+		if d.IsDir() && path != root && strings.HasPrefix(d.Name(), ".") {
+			return filepath.SkipDir
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		objects = append(objects, other.BackupObject{Key: filepath.ToSlash(rel), Size: info.Size(), ModTime: info.ModTime()})
+		return nil
+	})
+	return objects, err
+}
+
+func (*LocalBackup) DeleteBackup(key string) error {
+	target := filepath.Join(global.Config.Backup.Dir(), filepath.FromSlash(key))
+	if err := os.Remove(target); err != nil {
+		return err
+	}
+	// 目录为空时一并删除
+	_ = os.Remove(filepath.Dir(target))
+	return nil
+}
+
+// QiniuBackup 将备份保存在七牛云的私有空间中，对象名称以备份目录作为前缀
+type QiniuBackup struct{}
+
+func (*QiniuBackup) SaveBackup(key, localPath string) error {
+	putPolicy := storage.PutPolicy{Scope: global.Config.Backup.Bucket}
+	mac := qbox.NewMac(global.Config.Qiniu.AccessKey, global.Config.Qiniu.SecretKey)
+	upToken := putPolicy.UploadToken(mac)
+	formUploader := storage.NewFormUploader(qiniuConfig())
+	putRet := storage.PutRet{}
+	return formUploader.PutFile(context.Background(), &putRet, upToken, qiniuBackupPrefix()+key, localPath, nil)
+}
+
+func (*QiniuBackup) ListBackups() ([]other.BackupObject, error) {
+	mac := qbox.NewMac(global.Config.Qiniu.AccessKey, global.Config.Qiniu.SecretKey)
+	bucketManager := storage.NewBucketManager(mac, qiniuConfig())
+	prefix := qiniuBackupPrefix()
+
+	var objects []other.BackupObject
+	marker := ""
+	for {
+		entries, _, nextMarker, hasNext, err := bucketManager.ListFiles(global.Config.Backup.Bucket, prefix, "", marker, 1000)
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			objects = append(objects, other.BackupObject{
+				Key:     strings.TrimPrefix(entry.Key, prefix),
+				Size:    entry.Fsize,
+				ModTime: time.Unix(0, entry.PutTime*100), // 上传时间的单位为 100 纳秒
+			})
+		}
+		if !hasNext {
+			return objects, nil
+		}
+		marker = nextMarker
+	}
+}
+
+func (*QiniuBackup) DeleteBackup(key string) error {
+	mac := qbox.NewMac(global.Config.Qiniu.AccessKey, global.Config.Qiniu.SecretKey)
+	bucketManager := storage.NewBucketManager(mac, qiniuConfig())
+	return bucketManager.Delete(global.Config.Backup.Bucket, qiniuBackupPrefix()+key)
+}
+
+// qiniuBackupPrefix 返回七牛云中备份对象名称的前缀
+func qiniuBackupPrefix() string {
+	return global.Config.Backup.Dir() + "/"
+}
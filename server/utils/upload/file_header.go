@@ -0,0 +1,29 @@
+package upload
+
+import (
+	"bytes"
+	"mime/multipart"
+)
+
+// NewFileHeader 将内存中的文件内容包装为 multipart.FileHeader，以便复用 OSS 的上传接口
+func NewFileHeader(filename string, data []byte) (*multipart.FileHeader, error) {
+	var buf bytes.Buffer
+	writer := multipart.NewWriter(&buf)
+	part, err := writer.CreateFormFile("file", filename)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := part.Write(data); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+
+	// 内存上限大于文件大小，保证文件内容不会被写入临时文件
+	form, err := multipart.NewReader(&buf, writer.Boundary()).ReadForm(int64(len(data)) + 1<<20)
+	if err != nil {
+		return nil, err
+	}
+	return form.File["file"][0], nil
+}